@@ -0,0 +1,148 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/watchlist-kata/media/internal/config"
+)
+
+// Version и Commit задаются при сборке через -ldflags "-X ..."
+var (
+	Version = "dev"
+	Commit  = ""
+)
+
+// LevelController управляет уровнем логирования во время работы
+type LevelController interface {
+	Level() slog.Level
+	SetLevel(level slog.Level)
+}
+
+// AdminServer представляет собой HTTP сервер для отладки и администрирования
+type AdminServer struct {
+	server *http.Server
+	cfg    *config.Config
+	levels LevelController
+	logger *slog.Logger
+}
+
+// NewAdminServer создает новый AdminServer
+func NewAdminServer(addr string, cfg *config.Config, levels LevelController, logger *slog.Logger) *AdminServer {
+	s := &AdminServer{
+		cfg:    cfg,
+		levels: levels,
+		logger: logger,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.HandleFunc("GET /buildinfo", s.handleBuildInfo)
+	mux.HandleFunc("GET /config", s.handleConfig)
+	mux.HandleFunc("GET /loglevel", s.handleGetLogLevel)
+	mux.HandleFunc("PUT /loglevel", s.handleSetLogLevel)
+
+	s.server = &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	return s
+}
+
+// Start запускает admin сервер и блокируется до его остановки
+func (s *AdminServer) Start() error {
+	s.logger.Info("Starting admin HTTP server", "addr", s.server.Addr)
+	if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failed to serve admin HTTP: %w", err)
+	}
+	return nil
+}
+
+// Shutdown корректно останавливает admin сервер
+func (s *AdminServer) Shutdown(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
+
+// buildInfo описывает сборку сервиса
+type buildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	GoVersion string `json:"go_version"`
+}
+
+// handleBuildInfo возвращает версию, коммит и версию Go
+func (s *AdminServer) handleBuildInfo(w http.ResponseWriter, r *http.Request) {
+	info := buildInfo{
+		Version:   Version,
+		Commit:    Commit,
+		GoVersion: runtime.Version(),
+	}
+	if info.Commit == "" {
+		if bi, ok := debug.ReadBuildInfo(); ok {
+			for _, setting := range bi.Settings {
+				if setting.Key == "vcs.revision" {
+					info.Commit = setting.Value
+				}
+			}
+		}
+	}
+	s.writeJSON(w, http.StatusOK, info)
+}
+
+// handleConfig возвращает безопасную для показа часть конфигурации
+func (s *AdminServer) handleConfig(w http.ResponseWriter, r *http.Request) {
+	s.writeJSON(w, http.StatusOK, s.cfg.Public())
+}
+
+// handleGetLogLevel возвращает текущий уровень логирования
+func (s *AdminServer) handleGetLogLevel(w http.ResponseWriter, r *http.Request) {
+	s.writeJSON(w, http.StatusOK, map[string]string{"level": s.levels.Level().String()})
+}
+
+// handleSetLogLevel меняет уровень логирования, например: PUT /loglevel с телом "warn"
+func (s *AdminServer) handleSetLogLevel(w http.ResponseWriter, r *http.Request) {
+	value := r.URL.Query().Get("level")
+	if value == "" {
+		body, err := io.ReadAll(io.LimitReader(r.Body, 64))
+		if err != nil {
+			http.Error(w, "failed to read body", http.StatusBadRequest)
+			return
+		}
+		value = strings.TrimSpace(string(body))
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(value)); err != nil {
+		http.Error(w, fmt.Sprintf("invalid level %q", value), http.StatusBadRequest)
+		return
+	}
+
+	previous := s.levels.Level()
+	s.levels.SetLevel(level)
+	s.logger.Warn("Log level changed", "from", previous.String(), "to", level.String())
+	s.writeJSON(w, http.StatusOK, map[string]string{"level": level.String()})
+}
+
+// writeJSON сериализует ответ в JSON
+func (s *AdminServer) writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		s.logger.Error("Failed to write admin response", "error", err)
+	}
+}
@@ -0,0 +1,111 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+
+	"github.com/watchlist-kata/media/internal/audit"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Ключи метаданных, которые gateway заполняет после аутентификации.
+// Клиент может передать их сам, поэтому им можно верить, только если запрос пришел через gateway:
+// либо gRPC порт недоступен никому, кроме gateway, либо gateway передает общий секрет GATEWAY_TOKEN
+const (
+	metadataUserID       = "x-user-id"
+	metadataUserRoles    = "x-user-roles"
+	metadataGatewayToken = "x-gateway-token"
+)
+
+// roleAdmin - роль администратора
+const roleAdmin = "admin"
+
+// hasRole проверяет, что у вызывающего есть роль из метаданных x-user-roles (через запятую)
+func hasRole(ctx context.Context, role string) bool {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return false
+	}
+	for _, value := range md.Get(metadataUserRoles) {
+		for _, r := range strings.Split(value, ",") {
+			if strings.TrimSpace(r) == role {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// userID возвращает ID пользователя из метаданных x-user-id или пустую строку
+func userID(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	for _, value := range md.Get(metadataUserID) {
+		if value = strings.TrimSpace(value); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// trustedIdentity удаляет x-user-id и x-user-roles из метаданных запроса без секрета gateway.
+// Пустой gatewayToken означает, что проверку выполняет сеть: порт доступен только gateway
+func trustedIdentity(ctx context.Context, gatewayToken string) context.Context {
+	if gatewayToken == "" {
+		return ctx
+	}
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+	for _, value := range md.Get(metadataGatewayToken) {
+		if subtle.ConstantTimeCompare([]byte(value), []byte(gatewayToken)) == 1 {
+			return ctx
+		}
+	}
+	md = md.Copy()
+	md.Delete(metadataUserID)
+	md.Delete(metadataUserRoles)
+	return metadata.NewIncomingContext(ctx, md)
+}
+
+// identityInterceptor отбрасывает данные о пользователе из запросов, пришедших не через gateway
+func identityInterceptor(gatewayToken string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(trustedIdentity(ctx, gatewayToken), req)
+	}
+}
+
+// identityStreamInterceptor - identityInterceptor для потоковых вызовов
+func identityStreamInterceptor(gatewayToken string) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &contextStream{ServerStream: ss, ctx: trustedIdentity(ss.Context(), gatewayToken)})
+	}
+}
+
+// auditContext добавляет в контекст автора изменений и ID запроса
+func auditContext(ctx context.Context) context.Context {
+	ctx = audit.WithRequestID(ctx, GetRequestID(ctx))
+	if user := userID(ctx); user != "" {
+		ctx = audit.WithActor(ctx, user)
+	}
+	return ctx
+}
+
+// auditInterceptor передает автора изменений и ID запроса в контекст для истории изменений
+func auditInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(auditContext(ctx), req)
+	}
+}
+
+// auditStreamInterceptor - auditInterceptor для потоковых вызовов
+func auditStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &contextStream{ServerStream: ss, ctx: auditContext(ss.Context())})
+	}
+}
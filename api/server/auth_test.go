@@ -0,0 +1,42 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+)
+
+func TestTrustedIdentity(t *testing.T) {
+	tests := []struct {
+		name         string
+		gatewayToken string
+		metadata     []string
+		wantAdmin    bool
+		wantUser     string
+	}{
+		{name: "no token configured trusts metadata", metadata: []string{metadataUserRoles, "editor, admin", metadataUserID, "u1"}, wantAdmin: true, wantUser: "u1"},
+		{name: "matching token", gatewayToken: "secret", metadata: []string{metadataGatewayToken, "secret", metadataUserRoles, "admin", metadataUserID, "u1"}, wantAdmin: true, wantUser: "u1"},
+		{name: "missing token", gatewayToken: "secret", metadata: []string{metadataUserRoles, "admin", metadataUserID, "u1"}},
+		{name: "wrong token", gatewayToken: "secret", metadata: []string{metadataGatewayToken, "guess", metadataUserRoles, "admin", metadataUserID, "u1"}},
+		{name: "role is matched exactly", metadata: []string{metadataUserRoles, "administrator,superadmin"}},
+		{name: "no metadata", gatewayToken: "secret"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.metadata != nil {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(tt.metadata...))
+			}
+			ctx = trustedIdentity(ctx, tt.gatewayToken)
+
+			if got := hasRole(ctx, roleAdmin); got != tt.wantAdmin {
+				t.Errorf("hasRole(admin) = %v, want %v", got, tt.wantAdmin)
+			}
+			if got := userID(ctx); got != tt.wantUser {
+				t.Errorf("userID = %q, want %q", got, tt.wantUser)
+			}
+		})
+	}
+}
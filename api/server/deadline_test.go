@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/watchlist-kata/media/internal/config"
+)
+
+func TestApplyDeadline(t *testing.T) {
+	deadlines := map[string]config.RPCDeadline{
+		"*":               {Default: 5 * time.Second, Max: 30 * time.Second},
+		"GetMediasByName": {Default: 8 * time.Second},
+		"SaveMedia":       {Max: 10 * time.Second},
+	}
+
+	tests := []struct {
+		name           string
+		deadlines      map[string]config.RPCDeadline
+		method         string
+		clientDeadline time.Duration // 0 - клиент не передал дедлайн
+		want           time.Duration // 0 - дедлайна нет
+	}{
+		{name: "default from wildcard", deadlines: deadlines, method: "/media.MediaService/GetMediaByID", want: 5 * time.Second},
+		{name: "method overrides wildcard", deadlines: deadlines, method: "/media.MediaService/GetMediasByName", want: 8 * time.Second},
+		{name: "client deadline below max is kept", deadlines: deadlines, method: "/media.MediaService/GetMediaByID", clientDeadline: 20 * time.Second, want: 20 * time.Second},
+		{name: "client deadline above max is capped", deadlines: deadlines, method: "/media.MediaService/GetMediaByID", clientDeadline: time.Minute, want: 30 * time.Second},
+		{name: "method without default", deadlines: deadlines, method: "/media.MediaService/SaveMedia", want: 0},
+		{name: "method max caps client", deadlines: deadlines, method: "/media.MediaService/SaveMedia", clientDeadline: time.Minute, want: 10 * time.Second},
+		{name: "no settings", deadlines: nil, method: "/media.MediaService/GetMediaByID", want: 0},
+		{name: "no settings keeps client deadline", deadlines: nil, method: "/media.MediaService/GetMediaByID", clientDeadline: time.Minute, want: time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.clientDeadline > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.clientDeadline)
+				defer cancel()
+			}
+
+			ctx, cancel := applyDeadline(ctx, tt.method, tt.deadlines)
+			defer cancel()
+
+			deadline, ok := ctx.Deadline()
+			if tt.want == 0 {
+				if ok {
+					t.Fatalf("unexpected deadline in %v", time.Until(deadline))
+				}
+				return
+			}
+			if !ok {
+				t.Fatalf("no deadline, want %v", tt.want)
+			}
+			if got := time.Until(deadline); got > tt.want || got < tt.want-time.Second {
+				t.Errorf("deadline in %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStreamDeadlines(t *testing.T) {
+	tests := []struct {
+		name      string
+		deadlines map[string]config.RPCDeadline
+		method    string
+		want      time.Duration
+	}{
+		{name: "export has long default", deadlines: map[string]config.RPCDeadline{"*": {Default: 5 * time.Second}}, method: "/media.MediaService/ExportMedia", want: time.Hour},
+		{name: "export setting overrides default", deadlines: map[string]config.RPCDeadline{"ExportMedia": {Default: 2 * time.Hour}}, method: "/media.MediaService/ExportMedia", want: 2 * time.Hour},
+		{name: "watch ignores wildcard", deadlines: map[string]config.RPCDeadline{"*": {Default: 5 * time.Second}}, method: "/media.MediaService/WatchMediaChanges", want: 0},
+		{name: "search stream uses wildcard", deadlines: map[string]config.RPCDeadline{"*": {Default: 5 * time.Second}}, method: "/media.MediaService/SearchMediaStream", want: 5 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := applyDeadline(context.Background(), tt.method, streamDeadlines(tt.deadlines))
+			defer cancel()
+
+			deadline, ok := ctx.Deadline()
+			if tt.want == 0 {
+				if ok {
+					t.Fatalf("unexpected deadline in %v", time.Until(deadline))
+				}
+				return
+			}
+			if !ok {
+				t.Fatalf("no deadline, want %v", tt.want)
+			}
+			if got := time.Until(deadline); got > tt.want || got < tt.want-time.Second {
+				t.Errorf("deadline in %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/watchlist-kata/media/internal/kinopoisk"
+	"github.com/watchlist-kata/media/internal/repository"
+	"github.com/watchlist-kata/media/internal/service"
+	"github.com/watchlist-kata/media/internal/validation"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorDomain - домен для google.rpc.ErrorInfo
+const errorDomain = "media.watchlist-kata"
+
+// Стабильные коды причин для google.rpc.ErrorInfo
+const (
+	ReasonMediaNotFound        = "MEDIA_NOT_FOUND"
+	ReasonDuplicateKinopoiskID = "DUPLICATE_KINOPOISK_ID"
+	ReasonKinopoiskIDMismatch  = "KINOPOISK_ID_MISMATCH"
+	ReasonVersionConflict      = "VERSION_CONFLICT"
+	ReasonInvalidPageToken     = "INVALID_PAGE_TOKEN"
+	ReasonInvalidArgument      = "INVALID_ARGUMENT"
+	ReasonShuttingDown         = "SHUTTING_DOWN"
+	ReasonKinopoiskNotFound    = "KINOPOISK_FILM_NOT_FOUND"
+	ReasonKinopoiskRateLimited = "KINOPOISK_RATE_LIMITED"
+)
+
+// domainError описывает соответствие доменной ошибки gRPC статусу
+type domainError struct {
+	err    error
+	code   codes.Code
+	reason string
+}
+
+// domainErrors - известные доменные ошибки и их gRPC представление
+var domainErrors = []domainError{
+	{err: repository.ErrMediaNotFound, code: codes.NotFound, reason: ReasonMediaNotFound},
+	{err: repository.ErrDuplicateKinopoiskID, code: codes.AlreadyExists, reason: ReasonDuplicateKinopoiskID},
+	{err: repository.ErrKinopoiskIDMismatch, code: codes.FailedPrecondition, reason: ReasonKinopoiskIDMismatch},
+	{err: repository.ErrVersionConflict, code: codes.Aborted, reason: ReasonVersionConflict},
+	{err: repository.ErrInvalidCursor, code: codes.InvalidArgument, reason: ReasonInvalidPageToken},
+	{err: service.ErrShuttingDown, code: codes.Unavailable, reason: ReasonShuttingDown},
+	{err: kinopoisk.ErrFilmNotFound, code: codes.NotFound, reason: ReasonKinopoiskNotFound},
+	{err: kinopoisk.ErrRateLimited, code: codes.ResourceExhausted, reason: ReasonKinopoiskRateLimited},
+}
+
+// fieldViolations накапливает нарушения валидации по полям
+type fieldViolations []*errdetails.BadRequest_FieldViolation
+
+// add добавляет нарушение для поля
+func (v *fieldViolations) add(field, description string) {
+	*v = append(*v, &errdetails.BadRequest_FieldViolation{Field: field, Description: description})
+}
+
+// err возвращает InvalidArgument с google.rpc.BadRequest или nil, если нарушений нет
+func (v fieldViolations) err() error {
+	if len(v) == 0 {
+		return nil
+	}
+	st := status.New(codes.InvalidArgument, fmt.Sprintf("invalid request: %d invalid field(s)", len(v)))
+	detailed, err := st.WithDetails(
+		&errdetails.BadRequest{FieldViolations: v},
+		&errdetails.ErrorInfo{Reason: ReasonInvalidArgument, Domain: errorDomain},
+	)
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+// toStatusError преобразует ошибку сервиса в gRPC статус.
+// Доменные ошибки получают свой код и google.rpc.ErrorInfo, остальные - codes.Internal с message
+func toStatusError(err error, metadata map[string]string, format string, args ...any) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+
+	var verr *validation.Error
+	if errors.As(err, &verr) {
+		var violations fieldViolations
+		for _, v := range verr.Violations {
+			violations.add(v.Field, v.Description)
+		}
+		return violations.err()
+	}
+
+	for _, de := range domainErrors {
+		if !errors.Is(err, de.err) {
+			continue
+		}
+		st := status.New(de.code, err.Error())
+		detailed, detailErr := st.WithDetails(&errdetails.ErrorInfo{
+			Reason:   de.reason,
+			Domain:   errorDomain,
+			Metadata: metadata,
+		})
+		if detailErr != nil {
+			return st.Err()
+		}
+		return detailed.Err()
+	}
+
+	return status.Errorf(codes.Internal, "%s: %v", fmt.Sprintf(format, args...), err)
+}
+
+// idMetadata возвращает метаданные ErrorInfo для ID медиа
+func idMetadata(id int64) map[string]string {
+	return map[string]string{"id": strconv.FormatInt(id, 10)}
+}
+
+// kinopoiskMetadata возвращает метаданные ErrorInfo для kinopoisk_id
+func kinopoiskMetadata(kinopoiskID int64) map[string]string {
+	return map[string]string{"kinopoisk_id": strconv.FormatInt(kinopoiskID, 10)}
+}
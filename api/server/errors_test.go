@@ -0,0 +1,68 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/watchlist-kata/media/internal/kinopoisk"
+	"github.com/watchlist-kata/media/internal/repository"
+	"github.com/watchlist-kata/media/internal/validation"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestToStatusError(t *testing.T) {
+	verr := &validation.Error{}
+	verr.Add("kinopoisk_id", "must be greater than 0")
+
+	tests := []struct {
+		name       string
+		err        error
+		wantCode   codes.Code
+		wantReason string
+	}{
+		{
+			name:       "film not found in Kinopoisk",
+			err:        fmt.Errorf("Failed to ImportMedia: %w", fmt.Errorf("failed to get film 301 from Kinopoisk: %w", kinopoisk.ErrFilmNotFound)),
+			wantCode:   codes.NotFound,
+			wantReason: ReasonKinopoiskNotFound,
+		},
+		{
+			name:       "Kinopoisk rate limit",
+			err:        fmt.Errorf("Failed to RefreshMedia: %w", kinopoisk.ErrRateLimited),
+			wantCode:   codes.ResourceExhausted,
+			wantReason: ReasonKinopoiskRateLimited,
+		},
+		{name: "media not found", err: fmt.Errorf("wrapped: %w", repository.ErrMediaNotFound), wantCode: codes.NotFound, wantReason: ReasonMediaNotFound},
+		{name: "duplicate kinopoisk id", err: repository.ErrDuplicateKinopoiskID, wantCode: codes.AlreadyExists, wantReason: ReasonDuplicateKinopoiskID},
+		{name: "validation", err: fmt.Errorf("wrapped: %w", verr.Err()), wantCode: codes.InvalidArgument, wantReason: ReasonInvalidArgument},
+		{name: "deadline", err: fmt.Errorf("wrapped: %w", context.DeadlineExceeded), wantCode: codes.DeadlineExceeded},
+		{name: "status is kept", err: status.Error(codes.PermissionDenied, "admin role required"), wantCode: codes.PermissionDenied},
+		{name: "unknown error", err: errors.New("connection refused"), wantCode: codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st, ok := status.FromError(toStatusError(tt.err, kinopoiskMetadata(301), "failed to import media"))
+			if !ok {
+				t.Fatal("toStatusError did not return a gRPC status")
+			}
+			if st.Code() != tt.wantCode {
+				t.Errorf("code = %v, want %v", st.Code(), tt.wantCode)
+			}
+
+			reason := ""
+			for _, detail := range st.Details() {
+				if info, ok := detail.(*errdetails.ErrorInfo); ok {
+					reason = info.Reason
+				}
+			}
+			if reason != tt.wantReason {
+				t.Errorf("reason = %q, want %q", reason, tt.wantReason)
+			}
+		})
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/watchlist-kata/media/internal/config"
 	"github.com/watchlist-kata/media/internal/service"
 	"github.com/watchlist-kata/protos/media"
 	"google.golang.org/grpc"
@@ -64,16 +65,17 @@ func (s *MediaServer) logError(ctx context.Context, methodName string, err error
 	s.Logger.ErrorContext(ctx, methodName+" failed", append(fields, "error", err, "stack", string(debug.Stack()))...)
 }
 
-// validateSaveMediaRequest проверяет входные данные
+// validateSaveMediaRequest проверяет наличие медиа в запросе.
+// Поля медиа проверяются сервисом, все нарушения возвращаются через toStatusError
 func (s *MediaServer) validateSaveMediaRequest(req *media.SaveMediaRequest) error {
+	var violations fieldViolations
 	if req == nil {
-		return status.Errorf(codes.InvalidArgument, "request cannot be nil")
+		violations.add("request", "request cannot be nil")
+		return violations.err()
 	}
 	if req.Media == nil {
-		return status.Errorf(codes.InvalidArgument, "media cannot be nil")
-	}
-	if req.Media.KinopoiskId <= 0 {
-		return status.Errorf(codes.InvalidArgument, "kinopoiskID must be greater than 0")
+		violations.add("media", "media cannot be nil")
+		return violations.err()
 	}
 	return nil
 }
@@ -94,7 +96,7 @@ func (s *MediaServer) SaveMedia(ctx context.Context, req *media.SaveMediaRequest
 
 	// Валидируем входные данные
 	if err := s.validateSaveMediaRequest(req); err != nil {
-		s.logError(ctx, "SaveMedia", err, "kinopoiskID", req.Media.KinopoiskId, "request_id", requestID)
+		s.logError(ctx, "SaveMedia", err, "request_id", requestID)
 		return nil, err
 	}
 
@@ -103,7 +105,7 @@ func (s *MediaServer) SaveMedia(ctx context.Context, req *media.SaveMediaRequest
 	m, err := s.svc.SaveMedia(ctx, req)
 	if err != nil {
 		s.logError(ctx, "SaveMedia", err, "kinopoiskID", req.Media.KinopoiskId, "request_id", requestID)
-		return nil, status.Errorf(codes.Internal, "failed to save media with kinopoiskID %d: %v", req.Media.KinopoiskId, err)
+		return nil, toStatusError(err, kinopoiskMetadata(req.Media.KinopoiskId), "failed to save media with kinopoiskID %d", req.Media.KinopoiskId)
 	}
 	return m, nil
 }
@@ -123,7 +125,7 @@ func (s *MediaServer) GetMediaByID(ctx context.Context, req *media.GetMediaByIDR
 	m, err := s.svc.GetMediaByID(ctx, req)
 	if err != nil {
 		s.logError(ctx, "GetMediaByID", err, "mediaID", req.Id, "request_id", requestID)
-		return nil, status.Errorf(codes.Internal, "failed to get media by ID %d: %v", req.Id, err)
+		return nil, toStatusError(err, idMetadata(req.Id), "failed to get media by ID %d", req.Id)
 	}
 	return m, nil
 }
@@ -138,12 +140,12 @@ func (s *MediaServer) GetMediasByName(ctx context.Context, req *media.GetMediasB
 	requestID := GetRequestID(ctx)
 	ctx = context.WithValue(ctx, contextMethodKey, "GetMediasByName")
 
-	s.Logger.InfoContext(ctx, "GetMediasByName called", "name", req.Name, "request_id", requestID)
+	s.Logger.InfoContext(ctx, "GetMediasByName called", "name", req.Name, "mode", req.Mode, "request_id", requestID)
 
 	mediaList, err := s.svc.GetMediasByName(ctx, req)
 	if err != nil {
 		s.logError(ctx, "GetMediasByName", err, "name", req.Name, "request_id", requestID)
-		return nil, status.Errorf(codes.Internal, "failed to get medias by name %s: %v", req.Name, err)
+		return nil, toStatusError(err, nil, "failed to get medias by name %s", req.Name)
 	}
 	return mediaList, nil
 }
@@ -158,16 +160,17 @@ func (s *MediaServer) UpdateMedia(ctx context.Context, req *media.SaveMediaReque
 	requestID := GetRequestID(ctx)
 	ctx = context.WithValue(ctx, contextMethodKey, "UpdateMedia")
 
+	if err := s.validateSaveMediaRequest(req); err != nil {
+		s.logError(ctx, "UpdateMedia", err, "request_id", requestID)
+		return nil, err
+	}
+
 	s.Logger.InfoContext(ctx, "UpdateMedia called", "kinopoiskID", req.Media.KinopoiskId, "request_id", requestID)
 
-	m, err := s.svc.UpdateMedia(ctx, req.Media)
+	m, err := s.svc.UpdateMedia(ctx, req)
 	if err != nil {
 		s.logError(ctx, "UpdateMedia", err, "kinopoiskID", req.Media.KinopoiskId, "request_id", requestID)
-		// Проверяем, является ли ошибка нарушением уникальности
-		if strings.Contains(err.Error(), "kinopoisk_id already exists") {
-			return nil, status.Errorf(codes.AlreadyExists, err.Error())
-		}
-		return nil, status.Errorf(codes.Internal, "failed to update media with kinopoiskID %d: %v", req.Media.KinopoiskId, err)
+		return nil, toStatusError(err, kinopoiskMetadata(req.Media.KinopoiskId), "failed to update media with kinopoiskID %d", req.Media.KinopoiskId)
 	}
 	return m, nil
 }
@@ -187,7 +190,7 @@ func (s *MediaServer) SearchKinopoisk(ctx context.Context, req *media.SearchKino
 	medias, err := s.svc.SearchKinopoisk(ctx, req.Name)
 	if err != nil {
 		s.logError(ctx, "SearchKinopoisk", err, "name", req.Name, "request_id", requestID)
-		return nil, status.Errorf(codes.Internal, "failed to search Kinopoisk with name %s: %v", req.Name, err)
+		return nil, toStatusError(err, nil, "failed to search Kinopoisk with name %s", req.Name)
 	}
 
 	return &media.MediaList{Medias: medias}, nil
@@ -208,11 +211,184 @@ func (s *MediaServer) DeleteMedia(ctx context.Context, req *media.DeleteMediaReq
 	resp, err := s.svc.DeleteMedia(ctx, req)
 	if err != nil {
 		s.logError(ctx, "DeleteMedia", err, "id", req.Id, "request_id", requestID)
-		return nil, status.Errorf(codes.Internal, "failed to delete media with id %d: %v", req.Id, err)
+		return nil, toStatusError(err, idMetadata(req.Id), "failed to delete media with id %d", req.Id)
+	}
+	return resp, nil
+}
+
+// ListMedia implements the ListMedia gRPC method
+func (s *MediaServer) ListMedia(ctx context.Context, req *media.ListMediaRequest) (*media.ListMediaResponse, error) {
+	if err := s.checkContextCancellation(ctx, "ListMedia"); err != nil {
+		return nil, err
+	}
+
+	ctx = context.WithValue(ctx, contextRequestIDKey, GetRequestID(ctx))
+	requestID := GetRequestID(ctx)
+	ctx = context.WithValue(ctx, contextMethodKey, "ListMedia")
+
+	s.Logger.InfoContext(ctx, "ListMedia called", "page_size", req.PageSize, "sort_by", req.SortBy.String(), "request_id", requestID)
+
+	resp, err := s.svc.ListMedia(ctx, req)
+	if err != nil {
+		s.logError(ctx, "ListMedia", err, "request_id", requestID)
+		return nil, toStatusError(err, nil, "failed to list media")
 	}
 	return resp, nil
 }
 
+// RestoreMedia implements the RestoreMedia gRPC method
+func (s *MediaServer) RestoreMedia(ctx context.Context, req *media.RestoreMediaRequest) (*media.Media, error) {
+	if err := s.checkContextCancellation(ctx, "RestoreMedia"); err != nil {
+		return nil, err
+	}
+
+	ctx = context.WithValue(ctx, contextRequestIDKey, GetRequestID(ctx))
+	requestID := GetRequestID(ctx)
+	ctx = context.WithValue(ctx, contextMethodKey, "RestoreMedia")
+
+	s.Logger.InfoContext(ctx, "RestoreMedia called", "id", req.Id, "request_id", requestID)
+
+	m, err := s.svc.RestoreMedia(ctx, req)
+	if err != nil {
+		s.logError(ctx, "RestoreMedia", err, "id", req.Id, "request_id", requestID)
+		return nil, toStatusError(err, idMetadata(req.Id), "failed to restore media with id %d", req.Id)
+	}
+	return m, nil
+}
+
+// ImportMedia implements the ImportMedia gRPC method
+func (s *MediaServer) ImportMedia(ctx context.Context, req *media.ImportMediaRequest) (*media.Media, error) {
+	if err := s.checkContextCancellation(ctx, "ImportMedia"); err != nil {
+		return nil, err
+	}
+
+	ctx = context.WithValue(ctx, contextRequestIDKey, GetRequestID(ctx))
+	requestID := GetRequestID(ctx)
+	ctx = context.WithValue(ctx, contextMethodKey, "ImportMedia")
+
+	s.Logger.InfoContext(ctx, "ImportMedia called", "kinopoisk_id", req.KinopoiskId, "request_id", requestID)
+
+	m, err := s.svc.ImportMedia(ctx, req.KinopoiskId)
+	if err != nil {
+		s.logError(ctx, "ImportMedia", err, "kinopoisk_id", req.KinopoiskId, "request_id", requestID)
+		return nil, toStatusError(err, kinopoiskMetadata(req.KinopoiskId), "failed to import media with kinopoisk_id %d", req.KinopoiskId)
+	}
+	return m, nil
+}
+
+// PurgeMedia implements the PurgeMedia gRPC method, available only to admins
+func (s *MediaServer) PurgeMedia(ctx context.Context, req *media.PurgeMediaRequest) (*media.DeleteMediaResponse, error) {
+	if err := s.checkContextCancellation(ctx, "PurgeMedia"); err != nil {
+		return nil, err
+	}
+
+	ctx = context.WithValue(ctx, contextRequestIDKey, GetRequestID(ctx))
+	requestID := GetRequestID(ctx)
+	ctx = context.WithValue(ctx, contextMethodKey, "PurgeMedia")
+
+	if !hasRole(ctx, roleAdmin) {
+		s.Logger.WarnContext(ctx, "PurgeMedia denied", "id", req.Id, "request_id", requestID)
+		return nil, status.Errorf(codes.PermissionDenied, "PurgeMedia requires the %s role", roleAdmin)
+	}
+
+	s.Logger.InfoContext(ctx, "PurgeMedia called", "id", req.Id, "request_id", requestID)
+
+	resp, err := s.svc.PurgeMedia(ctx, req)
+	if err != nil {
+		s.logError(ctx, "PurgeMedia", err, "id", req.Id, "request_id", requestID)
+		return nil, toStatusError(err, idMetadata(req.Id), "failed to purge media with id %d", req.Id)
+	}
+	return resp, nil
+}
+
+// GetMediaHistory implements the GetMediaHistory gRPC method
+func (s *MediaServer) GetMediaHistory(ctx context.Context, req *media.GetMediaHistoryRequest) (*media.GetMediaHistoryResponse, error) {
+	if err := s.checkContextCancellation(ctx, "GetMediaHistory"); err != nil {
+		return nil, err
+	}
+
+	ctx = context.WithValue(ctx, contextRequestIDKey, GetRequestID(ctx))
+	requestID := GetRequestID(ctx)
+	ctx = context.WithValue(ctx, contextMethodKey, "GetMediaHistory")
+
+	s.Logger.InfoContext(ctx, "GetMediaHistory called", "media_id", req.MediaId, "page_size", req.PageSize, "request_id", requestID)
+
+	resp, err := s.svc.GetMediaHistory(ctx, req)
+	if err != nil {
+		s.logError(ctx, "GetMediaHistory", err, "media_id", req.MediaId, "request_id", requestID)
+		return nil, toStatusError(err, idMetadata(req.MediaId), "failed to get history of media with id %d", req.MediaId)
+	}
+	return resp, nil
+}
+
+// WatchMediaChanges implements the WatchMediaChanges server-streaming gRPC method
+func (s *MediaServer) WatchMediaChanges(req *media.WatchMediaChangesRequest, stream grpc.ServerStreamingServer[media.MediaHistoryEntry]) error {
+	ctx := stream.Context()
+	if err := s.checkContextCancellation(ctx, "WatchMediaChanges"); err != nil {
+		return err
+	}
+
+	ctx = context.WithValue(ctx, contextRequestIDKey, GetRequestID(ctx))
+	requestID := GetRequestID(ctx)
+	ctx = context.WithValue(ctx, contextMethodKey, "WatchMediaChanges")
+
+	s.Logger.InfoContext(ctx, "WatchMediaChanges called", "media_ids", req.MediaIds, "after_sequence", req.AfterSequence, "request_id", requestID)
+
+	err := s.svc.WatchMediaChanges(ctx, req, stream.Send)
+	if err != nil {
+		if ctx.Err() != nil {
+			return status.FromContextError(ctx.Err()).Err()
+		}
+		s.logError(ctx, "WatchMediaChanges", err, "request_id", requestID)
+		return toStatusError(err, nil, "failed to watch media changes")
+	}
+	return nil
+}
+
+// ExportMedia implements the ExportMedia server-streaming gRPC method
+func (s *MediaServer) ExportMedia(req *media.ExportMediaRequest, stream grpc.ServerStreamingServer[media.Media]) error {
+	ctx := stream.Context()
+	if err := s.checkContextCancellation(ctx, "ExportMedia"); err != nil {
+		return err
+	}
+
+	ctx = context.WithValue(ctx, contextRequestIDKey, GetRequestID(ctx))
+	requestID := GetRequestID(ctx)
+	ctx = context.WithValue(ctx, contextMethodKey, "ExportMedia")
+
+	s.Logger.InfoContext(ctx, "ExportMedia called", "updated_since", req.UpdatedSince, "updated_before", req.UpdatedBefore, "after_id", req.AfterId, "include_deleted", req.IncludeDeleted, "request_id", requestID)
+
+	err := s.svc.ExportMedia(ctx, req, stream.Send)
+	if err != nil {
+		if ctx.Err() != nil {
+			return status.FromContextError(ctx.Err()).Err()
+		}
+		s.logError(ctx, "ExportMedia", err, "request_id", requestID)
+		return toStatusError(err, nil, "failed to export media")
+	}
+	return nil
+}
+
+// SearchMediaStream implements the SearchMediaStream server-streaming gRPC method
+func (s *MediaServer) SearchMediaStream(req *media.GetMediasByNameRequest, stream grpc.ServerStreamingServer[media.SearchMediaStreamResponse]) error {
+	ctx := stream.Context()
+	if err := s.checkContextCancellation(ctx, "SearchMediaStream"); err != nil {
+		return err
+	}
+
+	ctx = context.WithValue(ctx, contextRequestIDKey, GetRequestID(ctx))
+	requestID := GetRequestID(ctx)
+	ctx = context.WithValue(ctx, contextMethodKey, "SearchMediaStream")
+
+	s.Logger.InfoContext(ctx, "SearchMediaStream called", "name", req.Name, "mode", req.Mode, "request_id", requestID)
+
+	if err := s.svc.SearchMediaStream(ctx, req, stream.Send); err != nil {
+		s.logError(ctx, "SearchMediaStream", err, "name", req.Name, "request_id", requestID)
+		return toStatusError(err, nil, "failed to search medias by name %s", req.Name)
+	}
+	return nil
+}
+
 // loggingInterceptor is a gRPC interceptor for logging
 func loggingInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
@@ -246,12 +422,131 @@ func loggingInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
 	}
 }
 
+// contextStream заменяет контекст серверного потока
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context возвращает контекст потока
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// streamLoggingInterceptor логирует начало и завершение потоковых вызовов
+func streamLoggingInterceptor(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		ctx := ss.Context()
+		requestID := GetRequestID(ctx)
+		ctx = context.WithValue(ctx, contextRequestIDKey, requestID)
+
+		logger.InfoContext(ctx, "Stream started", "method", info.FullMethod, "request_id", requestID, "start_time", start.Format(time.RFC3339))
+
+		err := handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+
+		duration := time.Since(start)
+		if err != nil && status.Code(err) != codes.Canceled {
+			logger.ErrorContext(ctx, "Stream failed", "method", info.FullMethod, "request_id", requestID, "duration", duration, "error", err)
+		} else {
+			logger.InfoContext(ctx, "Stream finished", "method", info.FullMethod, "request_id", requestID, "duration", duration)
+		}
+		return err
+	}
+}
+
+// deadlineInterceptor применяет дедлайн по умолчанию, если клиент его не передал,
+// и ограничивает слишком большие дедлайны клиента
+func deadlineInterceptor(logger *slog.Logger, deadlines map[string]config.RPCDeadline) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, cancel := applyDeadline(ctx, info.FullMethod, deadlines)
+		defer cancel()
+
+		if deadline, ok := ctx.Deadline(); ok {
+			logger.DebugContext(ctx, "Request deadline", "method", info.FullMethod, "budget", time.Until(deadline))
+		}
+		return handler(ctx, req)
+	}
+}
+
+// streamDeadlineDefaults - дедлайны потоков, для которых нет отдельной настройки в RPC_DEADLINES.
+// Выгрузка идет долго, а подписка на изменения не ограничена по времени, поэтому "*" к ним не применяется
+var streamDeadlineDefaults = map[string]config.RPCDeadline{
+	"ExportMedia":       {Default: time.Hour},
+	"WatchMediaChanges": {},
+}
+
+// streamDeadlines дополняет настройки дедлайнов значениями по умолчанию для потоков
+func streamDeadlines(deadlines map[string]config.RPCDeadline) map[string]config.RPCDeadline {
+	merged := make(map[string]config.RPCDeadline, len(deadlines)+len(streamDeadlineDefaults))
+	for method, deadline := range streamDeadlineDefaults {
+		merged[method] = deadline
+	}
+	for method, deadline := range deadlines {
+		merged[method] = deadline
+	}
+	return merged
+}
+
+// streamDeadlineInterceptor применяет дедлайны к потоковым вызовам так же, как deadlineInterceptor
+func streamDeadlineInterceptor(logger *slog.Logger, deadlines map[string]config.RPCDeadline) grpc.StreamServerInterceptor {
+	deadlines = streamDeadlines(deadlines)
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, cancel := applyDeadline(ss.Context(), info.FullMethod, deadlines)
+		defer cancel()
+
+		if deadline, ok := ctx.Deadline(); ok {
+			logger.DebugContext(ctx, "Stream deadline", "method", info.FullMethod, "budget", time.Until(deadline))
+		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// applyDeadline возвращает контекст с дедлайном согласно настройкам метода
+func applyDeadline(ctx context.Context, fullMethod string, deadlines map[string]config.RPCDeadline) (context.Context, context.CancelFunc) {
+	methodName := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+	deadline, ok := deadlines[methodName]
+	if !ok {
+		deadline, ok = deadlines["*"]
+	}
+	if !ok {
+		return ctx, func() {}
+	}
+
+	clientDeadline, hasDeadline := ctx.Deadline()
+	switch {
+	case !hasDeadline && deadline.Default > 0:
+		return context.WithTimeout(ctx, deadline.Default)
+	case hasDeadline && deadline.Max > 0 && time.Until(clientDeadline) > deadline.Max:
+		return context.WithTimeout(ctx, deadline.Max)
+	default:
+		return ctx, func() {}
+	}
+}
+
+// NewGRPCServer создает gRPC сервер с логированием и дедлайнами.
+// gatewayToken - секрет, с которым gateway передает данные о пользователе (пусто - не проверять)
+func NewGRPCServer(logger *slog.Logger, deadlines map[string]config.RPCDeadline, gatewayToken string) *grpc.Server {
+	return grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			loggingInterceptor(logger),
+			identityInterceptor(gatewayToken),
+			auditInterceptor(),
+			deadlineInterceptor(logger, deadlines),
+		),
+		grpc.ChainStreamInterceptor(
+			streamLoggingInterceptor(logger),
+			identityStreamInterceptor(gatewayToken),
+			auditStreamInterceptor(),
+			streamDeadlineInterceptor(logger, deadlines),
+		),
+	)
+}
+
 // StartGRPCServer starts the gRPC server
 func StartGRPCServer(port string, svc service.Service, logger *slog.Logger, grpcServer *grpc.Server) error {
 	if grpcServer == nil {
-		grpcServer = grpc.NewServer(
-			grpc.UnaryInterceptor(loggingInterceptor(logger)),
-		)
+		grpcServer = NewGRPCServer(logger, nil, "")
 	}
 
 	// Формируем сообщение с портом
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/watchlist-kata/media/internal/config"
+	"github.com/watchlist-kata/media/pkg/logger"
+	"github.com/watchlist-kata/media/pkg/utils"
+)
+
+// runCommand выполняет подкоманду CLI, например "media migrate up" или "media import ids.txt"
+func runCommand(args []string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Для CLI достаточно логирования в stderr, stdout остается для вывода команд, например media export
+	cliLogger := slog.New(logger.NewStderrHandler())
+
+	db, sqlDB, err := utils.NewDatabaseConnection(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer utils.CloseDatabaseConnection(sqlDB, cliLogger)
+
+	switch args[0] {
+	case "migrate":
+		return runMigrate(db, cliLogger, args[1:])
+	case "import":
+		return runImport(cfg, db, cliLogger, args[1:])
+	case "export":
+		return runExport(cfg, db, cliLogger, args[1:])
+	default:
+		return fmt.Errorf("unknown command %q, expected one of: migrate, import, export", args[0])
+	}
+}
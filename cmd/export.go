@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"github.com/watchlist-kata/media/internal/config"
+	"github.com/watchlist-kata/media/internal/repository"
+	"github.com/watchlist-kata/media/internal/service"
+	"github.com/watchlist-kata/protos/media"
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/encoding/protojson"
+	"gorm.io/gorm"
+)
+
+// csvHeader - столбцы выгрузки в CSV
+var csvHeader = []string{
+	"id", "kinopoisk_id", "type", "name_en", "name_ru", "description", "year", "poster",
+	"countries", "genres", "created_at", "updated_at", "version", "deleted_at",
+}
+
+// runExport выполняет "media export [-format jsonl|csv|protobuf] [-since T] [-before T] [-after-id N] [-include-deleted] [-o FILE]".
+// Медиа выгружаются в порядке ID. JSONL можно загрузить обратно через media import,
+// protobuf - поток сообщений Media с длиной перед каждым. С -include-deleted выгружаются
+// и удаленные медиа с deleted_at, чтобы инкрементальная выгрузка передавала удаления
+func runExport(cfg *config.Config, db *gorm.DB, logger *slog.Logger, args []string) (err error) {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := flags.String("format", "jsonl", "output format: jsonl, csv or protobuf")
+	since := flags.String("since", "", "export only media updated at or after this RFC3339 time")
+	before := flags.String("before", "", "export only media updated before this RFC3339 time")
+	afterID := flags.Int64("after-id", 0, "export only media with ID greater than this")
+	includeDeleted := flags.Bool("include-deleted", false, "also export soft-deleted media with deleted_at, with -since those deleted at or after it")
+	outputPath := flags.String("o", "-", "output file, - for stdout")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("usage: media export [-format jsonl|csv|protobuf] [-since T] [-before T] [-after-id N] [-include-deleted] [-o FILE]")
+	}
+
+	output := io.WriteCloser(os.Stdout)
+	if *outputPath != "-" {
+		file, err := os.Create(*outputPath)
+		if err != nil {
+			return fmt.Errorf("failed to create output: %w", err)
+		}
+		output = file
+	}
+	// Ошибка закрытия файла означает, что выгрузка могла записаться не полностью
+	defer func() {
+		if closeErr := output.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("failed to close output: %w", closeErr)
+		}
+	}()
+	buffered := bufio.NewWriter(output)
+
+	write, flush, err := newExportWriter(*format, buffered)
+	if err != nil {
+		return err
+	}
+
+	repo := repository.NewPostgresRepository(db, logger, cfg.TrigramThreshold)
+	svc, err := service.NewMediaService(repo, logger, cfg, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create media service: %w", err)
+	}
+	if closer, ok := svc.(interface{ Close() }); ok {
+		defer closer.Close()
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	exported := 0
+	req := &media.ExportMediaRequest{UpdatedSince: *since, UpdatedBefore: *before, AfterId: *afterID, IncludeDeleted: *includeDeleted}
+	if err := svc.ExportMedia(ctx, req, func(m *media.Media) error {
+		exported++
+		return write(m)
+	}); err != nil {
+		return fmt.Errorf("export stopped after %d media: %w", exported, err)
+	}
+
+	if err := flush(); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	if err := buffered.Flush(); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	logger.Info("Export finished", "exported", exported, "format", *format)
+	return nil
+}
+
+// newExportWriter возвращает функцию записи одного медиа в формате format и функцию
+// завершения записи
+func newExportWriter(format string, w io.Writer) (write func(*media.Media) error, flush func() error, err error) {
+	switch format {
+	case "jsonl":
+		marshal := protojson.MarshalOptions{UseProtoNames: true}
+		return func(m *media.Media) error {
+			data, err := marshal.Marshal(m)
+			if err != nil {
+				return fmt.Errorf("failed to encode media %d: %w", m.Id, err)
+			}
+			_, err = w.Write(append(data, '\n'))
+			return err
+		}, func() error { return nil }, nil
+
+	case "csv":
+		cw := csv.NewWriter(w)
+		if err := cw.Write(csvHeader); err != nil {
+			return nil, nil, err
+		}
+		return func(m *media.Media) error {
+				return cw.Write([]string{
+					strconv.FormatInt(m.Id, 10), strconv.FormatInt(m.KinopoiskId, 10), m.Type, m.NameEn, m.NameRu,
+					m.Description, m.Year, m.Poster, m.Countries, m.Genres, m.CreatedAt, m.UpdatedAt,
+					strconv.FormatInt(m.Version, 10), m.DeletedAt,
+				})
+			}, func() error {
+				cw.Flush()
+				return cw.Error()
+			}, nil
+
+	case "protobuf":
+		return func(m *media.Media) error {
+			_, err := protodelim.MarshalTo(w, m)
+			return err
+		}, func() error { return nil }, nil
+
+	default:
+		return nil, nil, fmt.Errorf("unknown export format %q, expected jsonl, csv or protobuf", format)
+	}
+}
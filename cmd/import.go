@@ -0,0 +1,368 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/watchlist-kata/media/internal/audit"
+	"github.com/watchlist-kata/media/internal/config"
+	"github.com/watchlist-kata/media/internal/kinopoisk"
+	"github.com/watchlist-kata/media/internal/repository"
+	"github.com/watchlist-kata/media/internal/validation"
+	"github.com/watchlist-kata/protos/media"
+	"google.golang.org/protobuf/encoding/protojson"
+	"gorm.io/gorm"
+)
+
+// importActor - автор изменений, сделанных импортом из CLI
+const importActor = "cli-import"
+
+// Повторные попытки при превышении лимита запросов к Кинопоиску
+const (
+	importRateLimitRetries = 5
+	importRateLimitBackoff = 10 * time.Second
+)
+
+// importStats - счетчики результатов импорта
+type importStats struct {
+	created, updated, unchanged, skipped, failed int
+	failedLines                                  []int // Номера строк, которые не удалось импортировать
+}
+
+// importer создает и обновляет медиа напрямую через репозиторий
+type importer struct {
+	repo    repository.Repository
+	client  *kinopoisk.KPClient
+	limiter *kinopoisk.Limiter
+	logger  *slog.Logger
+	failed  io.Writer // Куда дописывать строки, которые не удалось импортировать (nil - не сохранять)
+	stats   importStats
+}
+
+// runImport выполняет "media import [-checkpoint FILE] [-failed FILE] [-interval 1s] [FILE|-]".
+// Каждая строка входных данных - ID Кинопоиска или медиа в JSON (например, из media export).
+// Медиа из JSON сохраняется как есть без запроса к Кинопоиску, по ID медиа загружается из Кинопоиска.
+// Медиа с тем же kinopoisk_id создается или обновляется, удаленные медиа из media export -include-deleted
+// пропускаются. Номер последней обработанной строки пишется
+// в checkpoint, повторный запуск с тем же файлом продолжает с нее. Строки, которые не удалось
+// импортировать, дописываются в файл -failed, чтобы их можно было импортировать повторно
+func runImport(cfg *config.Config, db *gorm.DB, logger *slog.Logger, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	checkpointPath := flags.String("checkpoint", "", "file to store progress in, the import resumes from it")
+	failedPath := flags.String("failed", "", "file to append lines that failed to import to, it can be imported again")
+	interval := flags.Duration("interval", time.Second, "pause between Kinopoisk requests")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 1 {
+		return fmt.Errorf("usage: media import [-checkpoint FILE] [-failed FILE] [-interval 1s] [FILE|-]")
+	}
+
+	input, total, err := openImportInput(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer input.Close()
+
+	skip, err := readCheckpoint(*checkpointPath)
+	if err != nil {
+		return err
+	}
+
+	client, err := kinopoisk.NewKinopoiskClient(cfg.KinopoiskAPIKey, logger)
+	if err != nil {
+		return fmt.Errorf("failed to initialize Kinopoisk client: %w", err)
+	}
+	imp := &importer{
+		repo:    repository.NewPostgresRepository(db, logger, cfg.TrigramThreshold),
+		client:  client,
+		limiter: kinopoisk.NewLimiter(*interval),
+		logger:  logger,
+	}
+	if *failedPath != "" {
+		failedFile, err := os.OpenFile(*failedPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return fmt.Errorf("failed to open failed lines file: %w", err)
+		}
+		defer failedFile.Close()
+		imp.failed = failedFile
+	}
+
+	// Ctrl+C останавливает импорт после текущей строки, прогресс сохраняется
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	ctx = audit.WithActor(ctx, importActor)
+	ctx = audit.WithRequestID(ctx, "import:"+time.Now().UTC().Format(time.RFC3339))
+
+	if skip > 0 {
+		logger.Info("Resuming import from checkpoint", "checkpoint", *checkpointPath, "skipped_lines", skip)
+	}
+
+	scanner := bufio.NewScanner(input)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024) // JSON с длинным описанием не помещается в буфер по умолчанию
+	line := 0
+	for scanner.Scan() {
+		line++
+		if line <= skip {
+			continue
+		}
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		if err := imp.importLine(ctx, line, text); err != nil {
+			return fmt.Errorf("import stopped at line %d, rerun to resume: %w", line, err)
+		}
+		if err := writeCheckpoint(*checkpointPath, line); err != nil {
+			return err
+		}
+		printImportProgress(line, total, imp.stats)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read input: %w", err)
+	}
+
+	stats := imp.stats
+	logger.Info("Import finished", "lines", line, "created", stats.created, "updated", stats.updated,
+		"unchanged", stats.unchanged, "skipped", stats.skipped, "failed", stats.failed)
+	if stats.failed > 0 {
+		logger.Warn("Some lines were not imported", "lines", stats.failedLines, "failed_file", *failedPath)
+	}
+	return nil
+}
+
+// importLine импортирует одну строку. Ошибки, которые не исчезнут при повторе,
+// учитываются в stats.failed, остальные останавливают импорт
+func (imp *importer) importLine(ctx context.Context, line int, text string) error {
+	kinopoiskID, provided, err := parseImportLine(text)
+	if err == nil && provided.GetDeletedAt() != "" {
+		imp.logger.Info("Skipping deleted media", "line", line, "kinopoisk_id", kinopoiskID, "deleted_at", provided.DeletedAt)
+		imp.stats.skipped++
+		return nil
+	}
+	if err == nil {
+		if provided != nil {
+			err = imp.upsert(ctx, provided, false)
+		} else {
+			err = imp.importFromKinopoisk(ctx, kinopoiskID)
+		}
+	}
+
+	var verr *validation.Error
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &verr), errors.Is(err, errInvalidImportLine), errors.Is(err, kinopoisk.ErrFilmNotFound), errors.Is(err, repository.ErrKinopoiskIDMismatch):
+		imp.logger.Warn("Failed to import media", "line", line, "kinopoisk_id", kinopoiskID, "error", err)
+		return imp.recordFailure(line, text)
+	default:
+		return err
+	}
+}
+
+// recordFailure учитывает строку, которую не удалось импортировать, и сохраняет ее в файл -failed
+func (imp *importer) recordFailure(line int, text string) error {
+	imp.stats.failed++
+	imp.stats.failedLines = append(imp.stats.failedLines, line)
+	if imp.failed == nil {
+		return nil
+	}
+	if _, err := io.WriteString(imp.failed, text+"\n"); err != nil {
+		return fmt.Errorf("failed to write failed line: %w", err)
+	}
+	return nil
+}
+
+// importFromKinopoisk загружает медиа из Кинопоиска, повторяя запрос при превышении лимита
+func (imp *importer) importFromKinopoisk(ctx context.Context, kinopoiskID int64) error {
+	var kpMedia *media.Media
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err := imp.limiter.Wait(ctx); err != nil {
+			return err
+		}
+		kpMedia, err = imp.client.GetByID(ctx, kinopoiskID)
+		if !errors.Is(err, kinopoisk.ErrRateLimited) || attempt > importRateLimitRetries {
+			break
+		}
+		imp.logger.Warn("Kinopoisk rate limit reached, waiting", "kinopoisk_id", kinopoiskID, "attempt", attempt)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(importRateLimitBackoff * time.Duration(attempt)):
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get film %d from Kinopoisk: %w", kinopoiskID, err)
+	}
+	return imp.upsert(ctx, kpMedia, true)
+}
+
+// upsert создает медиа или обновляет сохраненное с тем же kinopoisk_id.
+// Медиа из Кинопоиска без изменений отмечается сверенным, чтобы оно считалось свежим
+func (imp *importer) upsert(ctx context.Context, m *media.Media, fromKinopoisk bool) error {
+	if err := validation.ValidateMedia(m); err != nil {
+		return err
+	}
+	// Жанры и страны сравниваются в том виде, в котором их сохраняет репозиторий
+	repository.NormalizeReferences(m)
+
+	existing, err := imp.repo.GetMediaByKinopoiskID(ctx, m.KinopoiskId)
+	if errors.Is(err, repository.ErrMediaNotFound) {
+		now := time.Now().Format(time.RFC3339)
+		m.CreatedAt, m.UpdatedAt = now, now
+		_, err = imp.repo.CreateMedia(ctx, m)
+		if err == nil {
+			imp.stats.created++
+			return nil
+		}
+		if !errors.Is(err, repository.ErrDuplicateKinopoiskID) {
+			return err
+		}
+		// Медиа сохранили параллельно - обновляем сохраненную запись
+		existing, err = imp.repo.GetMediaByKinopoiskID(ctx, m.KinopoiskId)
+	}
+	if err != nil {
+		return err
+	}
+
+	if sameMediaContent(existing, m) {
+		if fromKinopoisk {
+			if _, err := imp.repo.TouchMedia(ctx, existing.Id, existing.Version); err != nil {
+				return err
+			}
+		}
+		imp.stats.unchanged++
+		return nil
+	}
+
+	m.Id, m.Version = existing.Id, existing.Version
+	if _, err := imp.repo.UpdateMedia(ctx, m, nil); err != nil {
+		return err
+	}
+	imp.stats.updated++
+	return nil
+}
+
+// sameMediaContent сообщает, что импортируемое медиа не отличается от сохраненного.
+// Жанры и страны m должны быть приведены через repository.NormalizeReferences
+func sameMediaContent(existing, m *media.Media) bool {
+	return existing.Type == m.Type &&
+		existing.NameEn == m.NameEn &&
+		existing.NameRu == m.NameRu &&
+		existing.Description == m.Description &&
+		existing.Year == m.Year &&
+		existing.Poster == m.Poster &&
+		slices.Equal(existing.GenreList, m.GenreList) &&
+		slices.Equal(existing.CountryList, m.CountryList)
+}
+
+// errInvalidImportLine - строку нельзя разобрать как ID Кинопоиска или медиа в JSON
+var errInvalidImportLine = errors.New("invalid import line")
+
+// parseImportLine разбирает строку с ID Кинопоиска или медиа в JSON.
+// Для JSON возвращается и само медиа без ID и версии из исходной базы
+func parseImportLine(text string) (int64, *media.Media, error) {
+	if !strings.HasPrefix(text, "{") {
+		kinopoiskID, err := strconv.ParseInt(text, 10, 64)
+		if err != nil || kinopoiskID <= 0 {
+			return 0, nil, fmt.Errorf("%w: invalid kinopoisk_id %q", errInvalidImportLine, text)
+		}
+		return kinopoiskID, nil, nil
+	}
+
+	m := &media.Media{}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal([]byte(text), m); err != nil {
+		return 0, nil, fmt.Errorf("%w: invalid media JSON: %v", errInvalidImportLine, err)
+	}
+	if m.KinopoiskId <= 0 {
+		return 0, nil, fmt.Errorf("%w: media JSON without kinopoisk_id", errInvalidImportLine)
+	}
+	m.Id, m.Version = 0, 0
+	return m.KinopoiskId, m, nil
+}
+
+// openImportInput открывает файл или stdin ("-" или пусто) и считает строки файла для прогресса.
+// Для stdin количество строк неизвестно, возвращается 0
+func openImportInput(path string) (io.ReadCloser, int, error) {
+	if path == "" || path == "-" {
+		return io.NopCloser(os.Stdin), 0, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to open input: %w", err)
+	}
+	total := 0
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		total++
+	}
+	if err := scanner.Err(); err != nil {
+		file.Close()
+		return nil, 0, fmt.Errorf("failed to read input: %w", err)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		file.Close()
+		return nil, 0, fmt.Errorf("failed to rewind input: %w", err)
+	}
+	return file, total, nil
+}
+
+// readCheckpoint возвращает номер последней обработанной строки, 0 - начать сначала
+func readCheckpoint(path string) (int, error) {
+	if path == "" {
+		return 0, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+	line, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || line < 0 {
+		return 0, fmt.Errorf("invalid checkpoint %s: %q", path, data)
+	}
+	return line, nil
+}
+
+// writeCheckpoint атомарно сохраняет номер последней обработанной строки
+func writeCheckpoint(path string, line int) error {
+	if path == "" {
+		return nil
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(strconv.Itoa(line)+"\n"), 0o644); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	return nil
+}
+
+// printImportProgress выводит прогресс в stderr вместе с логами
+func printImportProgress(line, total int, stats importStats) {
+	position := strconv.Itoa(line)
+	if total > 0 {
+		position = fmt.Sprintf("%d/%d", line, total)
+	}
+	fmt.Fprintf(os.Stderr, "import: line %s, created %d, updated %d, unchanged %d, skipped %d, failed %d\n",
+		position, stats.created, stats.updated, stats.unchanged, stats.skipped, stats.failed)
+}
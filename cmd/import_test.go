@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/watchlist-kata/media/internal/repository"
+	"github.com/watchlist-kata/protos/media"
+)
+
+func TestParseImportLine(t *testing.T) {
+	tests := []struct {
+		name        string
+		text        string
+		kinopoiskID int64
+		payload     bool
+		wantErr     bool
+	}{
+		{name: "kinopoisk id", text: "301", kinopoiskID: 301},
+		{name: "export line", text: `{"id":"7","kinopoiskId":"301","type":"FILM","nameRu":"Матрица","version":"3"}`, kinopoiskID: 301, payload: true},
+		{name: "unknown fields are ignored", text: `{"kinopoisk_id":"301","rating":9.1}`, kinopoiskID: 301, payload: true},
+		{name: "zero id", text: "0", wantErr: true},
+		{name: "not a number", text: "matrix", wantErr: true},
+		{name: "broken json", text: `{"kinopoisk_id":`, wantErr: true},
+		{name: "json without kinopoisk id", text: `{"name_ru":"Матрица"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kinopoiskID, m, err := parseImportLine(tt.text)
+			if tt.wantErr {
+				if !errors.Is(err, errInvalidImportLine) {
+					t.Fatalf("error = %v, want errInvalidImportLine", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseImportLine failed: %v", err)
+			}
+			if kinopoiskID != tt.kinopoiskID {
+				t.Errorf("kinopoisk_id = %d, want %d", kinopoiskID, tt.kinopoiskID)
+			}
+			if (m != nil) != tt.payload {
+				t.Fatalf("payload = %v, want payload %v", m, tt.payload)
+			}
+			if m != nil && (m.Id != 0 || m.Version != 0) {
+				t.Errorf("id and version of the source database are kept: %d, %d", m.Id, m.Version)
+			}
+		})
+	}
+}
+
+func TestSameMediaContent(t *testing.T) {
+	stored := &media.Media{
+		Id: 7, Version: 3, KinopoiskId: 301, Type: "FILM", NameRu: "Матрица", UpdatedAt: "2024-01-01T00:00:00Z",
+		GenreList: []string{"фантастика", "боевик"}, Genres: "фантастика, боевик",
+		CountryList: []string{"США"}, Countries: "США",
+	}
+
+	tests := []struct {
+		name string
+		m    *media.Media
+		want bool
+	}{
+		{name: "server fields differ", m: &media.Media{KinopoiskId: 301, Type: "FILM", NameRu: "Матрица", GenreList: []string{"фантастика", "боевик"}, CountryList: []string{"США"}}, want: true},
+		{name: "only legacy strings", m: &media.Media{KinopoiskId: 301, Type: "FILM", NameRu: "Матрица", Genres: "фантастика,боевик", Countries: "США"}, want: true},
+		{name: "padded and duplicate names", m: &media.Media{KinopoiskId: 301, Type: "FILM", NameRu: "Матрица", GenreList: []string{" фантастика", "боевик ", "фантастика", ""}, CountryList: []string{"США", "США"}}, want: true},
+		{name: "name differs", m: &media.Media{KinopoiskId: 301, Type: "FILM", NameRu: "Матрица 2", GenreList: []string{"фантастика", "боевик"}, CountryList: []string{"США"}}},
+		{name: "genres differ", m: &media.Media{KinopoiskId: 301, Type: "FILM", NameRu: "Матрица", GenreList: []string{"фантастика"}, CountryList: []string{"США"}}},
+		{name: "genre order differs", m: &media.Media{KinopoiskId: 301, Type: "FILM", NameRu: "Матрица", Genres: "боевик, фантастика", Countries: "США"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Как в importer.upsert: сравнивается медиа, приведенное к виду репозитория
+			repository.NormalizeReferences(tt.m)
+			if got := sameMediaContent(stored, tt.m); got != tt.want {
+				t.Errorf("sameMediaContent = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImportLineSkipsDeletedMedia(t *testing.T) {
+	imp := &importer{logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	line := `{"id":"7","kinopoisk_id":"301","type":"FILM","name_ru":"Матрица","deleted_at":"2024-05-01T12:00:00Z"}`
+
+	// Репозиторий не задан: обращение к нему при пропуске строки вызвало бы панику
+	if err := imp.importLine(context.Background(), 1, line); err != nil {
+		t.Fatalf("importLine failed: %v", err)
+	}
+	if imp.stats.skipped != 1 || imp.stats.failed != 0 {
+		t.Errorf("stats = %+v, want one skipped line", imp.stats)
+	}
+}
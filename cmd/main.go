@@ -8,16 +8,30 @@ import (
 	"os/signal"
 	"sync"
 	"syscall"
+	"time"
 
+	"github.com/watchlist-kata/media/api/admin"
 	"github.com/watchlist-kata/media/api/server"
+	"github.com/watchlist-kata/media/internal/commands"
 	"github.com/watchlist-kata/media/internal/config"
+	"github.com/watchlist-kata/media/internal/jobs"
+	"github.com/watchlist-kata/media/internal/kinopoisk"
+	"github.com/watchlist-kata/media/internal/migrations"
 	"github.com/watchlist-kata/media/internal/repository"
 	"github.com/watchlist-kata/media/internal/service"
+	"github.com/watchlist-kata/media/pkg/logger"
 	"github.com/watchlist-kata/media/pkg/utils"
-	"google.golang.org/grpc"
 )
 
 func main() {
+	// Run CLI subcommand, e.g. "media migrate up"
+	if len(os.Args) > 1 {
+		if err := runCommand(os.Args[1:]); err != nil {
+			log.Fatalf("Command %s failed: %v", os.Args[1], err)
+		}
+		return
+	}
+
 	// Load configuration
 	cfg, err := config.LoadConfig()
 	if err != nil {
@@ -38,9 +52,22 @@ func main() {
 	}
 	defer utils.CloseDatabaseConnection(sqlDB, customLogger)
 
+	// Apply pending migrations on start if enabled
+	if cfg.DBAutoMigrate {
+		migrator, err := migrations.NewMigrator(db, customLogger)
+		if err != nil {
+			log.Fatalf("Failed to load migrations: %v", err)
+		}
+		if err := migrator.Up(context.Background()); err != nil {
+			log.Fatalf("Failed to apply migrations: %v", err)
+		}
+	}
+
 	// Create repository and service
-	repo := repository.NewPostgresRepository(db, customLogger)
-	svc, err := service.NewMediaService(repo, customLogger, cfg)
+	repo := repository.NewPostgresRepository(db, customLogger, cfg.TrigramThreshold)
+	// Background Kinopoisk requests of the resync job and stale media refresh share one limit
+	kinopoiskLimiter := kinopoisk.NewLimiter(cfg.KinopoiskGap)
+	svc, err := service.NewMediaService(repo, customLogger, cfg, kinopoiskLimiter)
 	if err != nil {
 		log.Fatalf("Failed to create media service: %v", err)
 	}
@@ -53,11 +80,11 @@ func main() {
 	var wg sync.WaitGroup
 
 	// Channel for server errors
-	errChan := make(chan error, 1)
+	errChan := make(chan error, 2)
 	defer close(errChan)
 
 	// Create gRPC server
-	grpcServer := grpc.NewServer()
+	grpcServer := server.NewGRPCServer(customLogger, cfg.RPCDeadlines, cfg.GatewayToken)
 
 	// Start gRPC server in a separate goroutine
 	wg.Add(1)
@@ -70,6 +97,79 @@ func main() {
 		}
 	}()
 
+	// Context for background jobs, canceled on shutdown
+	jobsCtx, stopJobs := context.WithCancel(ctx)
+	defer stopJobs()
+
+	// Start retention job for soft-deleted media if enabled
+	if cfg.DeletedRetention > 0 {
+		retentionJob := jobs.NewRetentionJob(repo, customLogger, cfg.RetentionInterval, cfg.DeletedRetention)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			retentionJob.Run(jobsCtx)
+		}()
+	}
+
+	// Start periodic resync of aging media with Kinopoisk if enabled
+	if cfg.ResyncInterval > 0 {
+		resyncJob := jobs.NewResyncJob(repo, svc, kinopoiskLimiter, customLogger, cfg.ResyncInterval, cfg.ResyncBatchSize)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resyncJob.Run(jobsCtx)
+		}()
+	}
+
+	// Start outbox relay publishing media change events
+	eventProducer, err := utils.NewEventProducer(cfg)
+	if err != nil {
+		log.Fatalf("Failed to create event producer: %v", err)
+	}
+	defer func() {
+		if err := eventProducer.Close(); err != nil {
+			customLogger.Error("Failed to close event producer", "error", err)
+		}
+	}()
+	outboxRelay := jobs.NewOutboxRelay(repo, eventProducer, cfg.EventsTopic, customLogger, cfg.OutboxInterval, cfg.OutboxBatchSize, cfg.OutboxMaxBackoff, cfg.OutboxRetention)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		outboxRelay.Run(jobsCtx)
+	}()
+
+	// Start consumer of RefreshMedia/ImportMedia commands if enabled
+	if cfg.CommandsTopic != "" {
+		commandConsumer, err := commands.NewConsumer(cfg.KafkaBrokers, cfg.CommandsGroup, cfg.CommandsTopic, cfg.CommandsDLQTopic,
+			cfg.CommandsWorkers, cfg.CommandsAttempts, eventProducer, svc, customLogger)
+		if err != nil {
+			log.Fatalf("Failed to create command consumer: %v", err)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			commandConsumer.Run(jobsCtx)
+		}()
+	}
+
+	// Start admin HTTP server if enabled
+	var adminServer *admin.AdminServer
+	if cfg.AdminAddr != "" {
+		levels, ok := customLogger.Handler().(*logger.MultiHandler)
+		if !ok {
+			log.Fatalf("Failed to start admin server: logger handler does not support level control")
+		}
+		adminServer = admin.NewAdminServer(cfg.AdminAddr, cfg, levels, customLogger)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := adminServer.Start(); err != nil {
+				errChan <- fmt.Errorf("admin server failed: %w", err)
+			}
+		}()
+	}
+
 	// Handle shutdown signals
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
@@ -85,6 +185,23 @@ func main() {
 		customLogger.Info("Context canceled, initiating shutdown")
 	}
 
+	// Stop background jobs
+	stopJobs()
+
+	// Stop admin server first so it doesn't outlive the gRPC server
+	if adminServer != nil {
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := adminServer.Shutdown(shutdownCtx); err != nil {
+			customLogger.Error("Failed to stop admin server", "error", err)
+		}
+		shutdownCancel()
+	}
+
+	// End long-lived streams, graceful stop waits for them
+	if closer, ok := svc.(interface{ Close() }); ok {
+		closer.Close()
+	}
+
 	// Perform graceful shutdown
 	utils.GracefulShutdown(ctx, grpcServer, sqlDB, customLogger, &wg)
 
@@ -0,0 +1,47 @@
+package audit
+
+import "context"
+
+// Акторы по умолчанию
+const (
+	// ActorAnonymous - вызов без данных аутентификации
+	ActorAnonymous = "anonymous"
+	// ActorKinopoiskSync - изменения, сделанные синхронизацией с Кинопоиском
+	ActorKinopoiskSync = "kinopoisk-sync"
+	// ActorRetention - окончательное удаление задачей очистки
+	ActorRetention = "retention-job"
+	// ActorResync - обновление из Кинопоиска фоновой задачей сверки
+	ActorResync = "resync-job"
+)
+
+// contextKey - тип ключей контекста пакета
+type contextKey int
+
+const (
+	actorKey contextKey = iota
+	requestIDKey
+)
+
+// WithActor возвращает контекст с автором изменений
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+// ActorFrom возвращает автора изменений из контекста или ActorAnonymous
+func ActorFrom(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey).(string); ok && actor != "" {
+		return actor
+	}
+	return ActorAnonymous
+}
+
+// WithRequestID возвращает контекст с ID запроса
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestIDFrom возвращает ID запроса из контекста или пустую строку
+func RequestIDFrom(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
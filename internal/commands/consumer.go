@@ -0,0 +1,359 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/watchlist-kata/media/internal/audit"
+	"github.com/watchlist-kata/media/internal/kinopoisk"
+	"github.com/watchlist-kata/media/internal/repository"
+	"github.com/watchlist-kata/media/internal/validation"
+	"github.com/watchlist-kata/protos/media"
+)
+
+// Type - тип команды
+type Type string
+
+const (
+	RefreshMedia Type = "RefreshMedia"
+	ImportMedia  Type = "ImportMedia"
+)
+
+// Command - тело сообщения с командой, например {"type":"ImportMedia","kinopoisk_id":301}
+type Command struct {
+	Type        Type  `json:"type"`
+	KinopoiskID int64 `json:"kinopoisk_id"`
+}
+
+// Заголовки сообщений
+const (
+	headerRequestID = "request_id" // ID запроса отправителя, попадает в историю изменений
+	headerUserID    = "user_id"    // Автор изменений, по умолчанию kinopoisk-sync
+
+	headerDLQError     = "dlq_error"
+	headerDLQTopic     = "dlq_original_topic"
+	headerDLQPartition = "dlq_original_partition"
+	headerDLQOffset    = "dlq_original_offset"
+)
+
+const (
+	// retryBaseBackoff - задержка перед первой повторной попыткой команды
+	retryBaseBackoff = time.Second
+	// commitInterval - как часто фиксировать смещения обработанных сообщений
+	commitInterval = time.Second
+	// dlqMaxAttempts - сколько раз пытаться отправить сообщение в dead-letter топик.
+	// Пока сообщение не отправлено, сессия не может завершиться, поэтому попытки ограничены
+	dlqMaxAttempts = 5
+)
+
+// Handler выполняет команды
+type Handler interface {
+	ImportMedia(ctx context.Context, kinopoiskID int64) (*media.Media, error)
+	RefreshMedia(ctx context.Context, kinopoiskID int64) (*media.Media, error)
+}
+
+// errNotProcessed - сообщение не выполнено и не отправлено в dead-letter топик.
+// Сессия завершается, чтобы прочитать его повторно с последнего зафиксированного смещения
+var errNotProcessed = errors.New("command was neither executed nor sent to the dead-letter topic")
+
+// poisonError - сообщение, которое не удастся обработать повторной попыткой
+type poisonError struct {
+	err error
+}
+
+func (e *poisonError) Error() string { return e.err.Error() }
+func (e *poisonError) Unwrap() error { return e.err }
+
+// Consumer читает команды из Kafka в группе потребителей и выполняет их через Handler.
+// Смещение фиксируется только после обработки сообщения и всех предыдущих в партиции,
+// сообщения, которые не удалось обработать, отправляются в dead-letter топик
+type Consumer struct {
+	group       sarama.ConsumerGroup
+	producer    sarama.SyncProducer
+	handler     Handler
+	logger      *slog.Logger
+	topic       string
+	dlqTopic    string
+	maxAttempts int
+	backoff     time.Duration // Задержка перед первой повторной попыткой, удваивается с каждой попыткой
+	slots       chan struct{} // Ограничивает число одновременно выполняемых команд
+}
+
+// NewConsumer создает группу потребителей команд.
+// producer используется для отправки сообщений в dlqTopic
+func NewConsumer(brokers []string, groupID, topic, dlqTopic string, concurrency, maxAttempts int, producer sarama.SyncProducer, handler Handler, logger *slog.Logger) (*Consumer, error) {
+	saramaCfg := sarama.NewConfig()
+	saramaCfg.Consumer.Offsets.Initial = sarama.OffsetOldest
+	saramaCfg.Consumer.Offsets.AutoCommit.Enable = false
+	saramaCfg.Consumer.Return.Errors = true
+
+	group, err := sarama.NewConsumerGroup(brokers, groupID, saramaCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create consumer group %s: %w", groupID, err)
+	}
+
+	return &Consumer{
+		group:       group,
+		producer:    producer,
+		handler:     handler,
+		logger:      logger,
+		topic:       topic,
+		dlqTopic:    dlqTopic,
+		maxAttempts: maxAttempts,
+		backoff:     retryBaseBackoff,
+		slots:       make(chan struct{}, concurrency),
+	}, nil
+}
+
+// Run читает команды до отмены контекста и закрывает группу
+func (c *Consumer) Run(ctx context.Context) {
+	c.logger.Info("Starting command consumer", "topic", c.topic, "dlq_topic", c.dlqTopic, "concurrency", cap(c.slots))
+	defer func() {
+		if err := c.group.Close(); err != nil {
+			c.logger.Error("Failed to close command consumer group", "error", err)
+		}
+	}()
+
+	go func() {
+		for err := range c.group.Errors() {
+			c.logger.Error("Command consumer error", "error", err)
+		}
+	}()
+
+	for ctx.Err() == nil {
+		// Consume возвращается при ребалансировке, поэтому вызывается в цикле
+		if err := c.group.Consume(ctx, []string{c.topic}, &groupHandler{consumer: c, ctx: ctx}); err != nil {
+			if errors.Is(err, sarama.ErrClosedConsumerGroup) {
+				return
+			}
+			c.logger.Error("Command consumer session failed", "error", err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(retryBaseBackoff):
+			}
+		}
+	}
+	c.logger.Info("Command consumer stopped")
+}
+
+// inflight - сообщение, которое выполняется или уже выполнено
+type inflight struct {
+	msg  *sarama.ConsumerMessage
+	done chan struct{}
+	ok   bool // Сообщение обработано или отправлено в dead-letter топик
+}
+
+// groupHandler реализует sarama.ConsumerGroupHandler для одной сессии
+type groupHandler struct {
+	consumer *Consumer
+	ctx      context.Context // Контекст выполнения команд, живет дольше сессии
+}
+
+func (h *groupHandler) Setup(sarama.ConsumerGroupSession) error   { return nil }
+func (h *groupHandler) Cleanup(sarama.ConsumerGroupSession) error { return nil }
+
+// ConsumeClaim выполняет сообщения партиции параллельно, но фиксирует смещения по порядку.
+// Если первое незафиксированное сообщение не удалось ни выполнить, ни отправить в dead-letter
+// топик, чтение партиции прекращается с ошибкой и сессия завершается
+func (h *groupHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	var pending []*inflight
+	ticker := time.NewTicker(commitInterval)
+	defer ticker.Stop()
+
+	// При завершении дожидаемся начатых команд, чтобы зафиксировать их смещения
+	defer func() {
+		for _, f := range pending {
+			<-f.done
+		}
+		h.markDone(session, pending)
+	}()
+
+	for {
+		select {
+		case msg, ok := <-claim.Messages():
+			if !ok {
+				return nil
+			}
+			select {
+			case h.consumer.slots <- struct{}{}:
+			case <-session.Context().Done():
+				return nil
+			}
+			f := &inflight{msg: msg, done: make(chan struct{})}
+			pending = append(pending, f)
+			go func() {
+				defer func() { <-h.consumer.slots }()
+				defer close(f.done)
+				f.ok = h.consumer.handle(h.ctx, session.Context().Done(), f.msg)
+			}()
+		case <-ticker.C:
+			var blocked bool
+			if pending, blocked = h.markDone(session, pending); blocked && session.Context().Err() == nil {
+				head := pending[0].msg
+				return fmt.Errorf("partition %d offset %d: %w", head.Partition, head.Offset, errNotProcessed)
+			}
+		case <-session.Context().Done():
+			return nil
+		}
+	}
+}
+
+// markDone фиксирует смещения выполненных сообщений с начала очереди и возвращает остаток.
+// blocked сообщает, что очередь начинается с выполненного, но необработанного сообщения:
+// дальше смещения не двинутся, пока оно не будет прочитано повторно
+func (h *groupHandler) markDone(session sarama.ConsumerGroupSession, pending []*inflight) (rest []*inflight, blocked bool) {
+	marked := 0
+	for _, f := range pending {
+		select {
+		case <-f.done:
+		default:
+			return h.commit(session, pending, marked), false
+		}
+		if !f.ok {
+			return h.commit(session, pending, marked), true
+		}
+		session.MarkMessage(f.msg, "")
+		marked++
+	}
+	return h.commit(session, pending, marked), false
+}
+
+// commit фиксирует отмеченные смещения и убирает их из очереди
+func (h *groupHandler) commit(session sarama.ConsumerGroupSession, pending []*inflight, marked int) []*inflight {
+	if marked > 0 {
+		session.Commit()
+	}
+	return pending[marked:]
+}
+
+// handle выполняет команду с повторными попытками. Сообщения, которые не удалось
+// выполнить, отправляются в dead-letter топик. Возвращает false, если сообщение
+// не обработано и не отправлено в dead-letter топик: контекст отменен, сессия
+// завершилась во время ожидания повтора или исчерпаны попытки отправки в dead-letter топик.
+// Такое сообщение не фиксируется, сессия завершается и оно будет прочитано повторно
+func (c *Consumer) handle(ctx context.Context, sessionDone <-chan struct{}, msg *sarama.ConsumerMessage) bool {
+	ctx = c.messageContext(ctx, msg)
+
+	var err error
+	for attempt := 1; attempt <= c.maxAttempts; attempt++ {
+		if err = c.execute(ctx, msg); err == nil {
+			return true
+		}
+		var poison *poisonError
+		if errors.As(err, &poison) || ctx.Err() != nil {
+			break
+		}
+		c.logger.WarnContext(ctx, "Command failed, retrying", "partition", msg.Partition, "offset", msg.Offset, "attempt", attempt, "error", err)
+		if attempt < c.maxAttempts {
+			select {
+			case <-ctx.Done():
+				return false
+			case <-sessionDone:
+				return false
+			case <-time.After(c.backoff << (attempt - 1)):
+			}
+		}
+	}
+	if ctx.Err() != nil {
+		return false
+	}
+
+	c.logger.ErrorContext(ctx, "Command failed, sending to dead-letter topic", "partition", msg.Partition, "offset", msg.Offset, "error", err)
+	for attempt := 1; ; attempt++ {
+		dlqErr := c.sendToDLQ(msg, err)
+		if dlqErr == nil {
+			return true
+		}
+		c.logger.ErrorContext(ctx, "Failed to send command to dead-letter topic", "partition", msg.Partition, "offset", msg.Offset, "attempt", attempt, "error", dlqErr)
+		if attempt == dlqMaxAttempts {
+			return false
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-sessionDone:
+			return false
+		case <-time.After(c.backoff << (attempt - 1)):
+		}
+	}
+}
+
+// execute разбирает и выполняет одну команду
+func (c *Consumer) execute(ctx context.Context, msg *sarama.ConsumerMessage) error {
+	var cmd Command
+	if err := json.Unmarshal(msg.Value, &cmd); err != nil {
+		return &poisonError{fmt.Errorf("failed to decode command: %w", err)}
+	}
+
+	var err error
+	switch cmd.Type {
+	case ImportMedia:
+		_, err = c.handler.ImportMedia(ctx, cmd.KinopoiskID)
+	case RefreshMedia:
+		_, err = c.handler.RefreshMedia(ctx, cmd.KinopoiskID)
+	default:
+		return &poisonError{fmt.Errorf("unknown command type %q", cmd.Type)}
+	}
+	if err != nil && isPermanent(err) {
+		return &poisonError{err}
+	}
+	if err == nil {
+		c.logger.InfoContext(ctx, "Command executed", "type", cmd.Type, "kinopoisk_id", cmd.KinopoiskID)
+	}
+	return err
+}
+
+// isPermanent определяет ошибки, которые не исчезнут при повторной попытке
+func isPermanent(err error) bool {
+	var verr *validation.Error
+	return errors.As(err, &verr) ||
+		errors.Is(err, repository.ErrMediaNotFound) ||
+		errors.Is(err, repository.ErrKinopoiskIDMismatch) ||
+		errors.Is(err, kinopoisk.ErrFilmNotFound)
+}
+
+// messageContext передает ID запроса и автора из заголовков сообщения в контекст
+func (c *Consumer) messageContext(ctx context.Context, msg *sarama.ConsumerMessage) context.Context {
+	requestID := fmt.Sprintf("kafka:%s/%d/%d", msg.Topic, msg.Partition, msg.Offset)
+	for _, header := range msg.Headers {
+		switch string(header.Key) {
+		case headerRequestID:
+			if len(header.Value) > 0 {
+				requestID = string(header.Value)
+			}
+		case headerUserID:
+			if len(header.Value) > 0 {
+				ctx = audit.WithActor(ctx, string(header.Value))
+			}
+		}
+	}
+	return audit.WithRequestID(ctx, requestID)
+}
+
+// sendToDLQ отправляет исходное сообщение в dead-letter топик с описанием ошибки
+func (c *Consumer) sendToDLQ(msg *sarama.ConsumerMessage, cause error) error {
+	headers := make([]sarama.RecordHeader, 0, len(msg.Headers)+4)
+	for _, header := range msg.Headers {
+		headers = append(headers, *header)
+	}
+	headers = append(headers,
+		sarama.RecordHeader{Key: []byte(headerDLQError), Value: []byte(cause.Error())},
+		sarama.RecordHeader{Key: []byte(headerDLQTopic), Value: []byte(msg.Topic)},
+		sarama.RecordHeader{Key: []byte(headerDLQPartition), Value: []byte(strconv.Itoa(int(msg.Partition)))},
+		sarama.RecordHeader{Key: []byte(headerDLQOffset), Value: []byte(strconv.FormatInt(msg.Offset, 10))},
+	)
+
+	_, _, err := c.producer.SendMessage(&sarama.ProducerMessage{
+		Topic:   c.dlqTopic,
+		Key:     sarama.ByteEncoder(msg.Key),
+		Value:   sarama.ByteEncoder(msg.Value),
+		Headers: headers,
+	})
+	return err
+}
@@ -0,0 +1,100 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/watchlist-kata/protos/media"
+)
+
+// fakeSession запоминает отмеченные смещения. Остальные методы не реализованы
+type fakeSession struct {
+	sarama.ConsumerGroupSession
+	ctx    context.Context
+	mu     sync.Mutex
+	marked []int64
+}
+
+func (s *fakeSession) Context() context.Context { return s.ctx }
+func (s *fakeSession) Commit()                  {}
+
+func (s *fakeSession) MarkMessage(msg *sarama.ConsumerMessage, metadata string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.marked = append(s.marked, msg.Offset)
+}
+
+// fakeClaim отдает сообщения из канала, который не закрывается
+type fakeClaim struct {
+	sarama.ConsumerGroupClaim
+	messages chan *sarama.ConsumerMessage
+}
+
+func (c *fakeClaim) Messages() <-chan *sarama.ConsumerMessage { return c.messages }
+
+// failingProducer не может отправить ни одного сообщения
+type failingProducer struct {
+	sarama.SyncProducer
+}
+
+func (p *failingProducer) SendMessage(msg *sarama.ProducerMessage) (int32, int64, error) {
+	return 0, 0, errors.New("broker is unavailable")
+}
+
+// okHandler успешно выполняет любую команду
+type okHandler struct{}
+
+func (okHandler) ImportMedia(ctx context.Context, kinopoiskID int64) (*media.Media, error) {
+	return &media.Media{KinopoiskId: kinopoiskID}, nil
+}
+
+func (okHandler) RefreshMedia(ctx context.Context, kinopoiskID int64) (*media.Media, error) {
+	return &media.Media{KinopoiskId: kinopoiskID}, nil
+}
+
+func TestConsumeClaimStopsWhenDeadLetterFails(t *testing.T) {
+	consumer := &Consumer{
+		producer:    &failingProducer{},
+		handler:     okHandler{},
+		logger:      slog.New(slog.NewTextHandler(io.Discard, nil)),
+		topic:       "media-commands",
+		dlqTopic:    "media-commands.dlq",
+		maxAttempts: 3,
+		backoff:     time.Millisecond,
+		slots:       make(chan struct{}, 4),
+	}
+
+	claim := &fakeClaim{messages: make(chan *sarama.ConsumerMessage, 4)}
+	command := []byte(`{"type":"RefreshMedia","kinopoisk_id":301}`)
+	claim.messages <- &sarama.ConsumerMessage{Topic: consumer.topic, Offset: 10, Value: command}
+	claim.messages <- &sarama.ConsumerMessage{Topic: consumer.topic, Offset: 11, Value: []byte("not a command")}
+	claim.messages <- &sarama.ConsumerMessage{Topic: consumer.topic, Offset: 12, Value: command}
+	claim.messages <- &sarama.ConsumerMessage{Topic: consumer.topic, Offset: 13, Value: command}
+
+	session := &fakeSession{ctx: context.Background()}
+	handler := &groupHandler{consumer: consumer, ctx: context.Background()}
+
+	result := make(chan error, 1)
+	go func() { result <- handler.ConsumeClaim(session, claim) }()
+
+	select {
+	case err := <-result:
+		if !errors.Is(err, errNotProcessed) {
+			t.Fatalf("ConsumeClaim error = %v, want errNotProcessed", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("ConsumeClaim did not exit after the dead-letter topic failed")
+	}
+
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	if len(session.marked) != 1 || session.marked[0] != 10 {
+		t.Errorf("marked offsets = %v, want only [10] before the failed offset 11", session.marked)
+	}
+}
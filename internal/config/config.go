@@ -2,28 +2,60 @@ package config
 
 import (
 	"fmt"
+	"log/slog"
+	"net"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
+// RPCDeadline задает дедлайны для одного gRPC метода
+type RPCDeadline struct {
+	Default time.Duration // Дедлайн, если клиент его не передал (0 - не задавать)
+	Max     time.Duration // Максимальный дедлайн, запрошенный клиентом (0 - без ограничения)
+}
+
 // Config содержит параметры конфигурации приложения
 type Config struct {
-	KinopoiskAPIKey string   // Ключ API для Кинопоиска
-	KinopoiskAPIURL string   // URL API для Кинопоиска
-	DBHost          string   // Хост базы данных
-	DBPort          string   // Порт базы данных
-	DBUser          string   // Пользователь базы данных
-	DBPassword      string   // Пароль базы данных
-	DBName          string   // Имя базы данных
-	DBSSLMode       string   // Режим SSL для базы данных
-	KafkaBrokers    []string // Список брокеров Kafka
-	KafkaTopic      string   // Тема Kafka
-	GRPCPort        string   // Порт для gRPC сервиса
-	ServiceName     string   // Имя сервиса
-	LogBufferSize   int      // Размер буфера для логов
+	KinopoiskAPIKey   string                 // Ключ API для Кинопоиска
+	KinopoiskAPIURL   string                 // URL API для Кинопоиска
+	DBHost            string                 // Хост базы данных
+	DBPort            string                 // Порт базы данных
+	DBUser            string                 // Пользователь базы данных
+	DBPassword        string                 // Пароль базы данных
+	DBName            string                 // Имя базы данных
+	DBSSLMode         string                 // Режим SSL для базы данных
+	KafkaBrokers      []string               // Список брокеров Kafka
+	KafkaTopic        string                 // Тема Kafka
+	GRPCPort          string                 // Порт для gRPC сервиса
+	ServiceName       string                 // Имя сервиса
+	LogBufferSize     int                    // Размер буфера для логов
+	LogLevel          slog.Level             // Минимальный уровень логирования
+	AdminAddr         string                 // Адрес admin HTTP сервера (пусто - выключен)
+	RPCDeadlines      map[string]RPCDeadline // Дедлайны по имени метода, "*" - для остальных
+	DBAutoMigrate     bool                   // Применять миграции при старте
+	LocalSearchMode   string                 // Режим поиска в локальной базе: fulltext, like или trigram
+	TrigramThreshold  float64                // Порог сходства для нечеткого поиска (0 - по умолчанию)
+	DeletedRetention  time.Duration          // Через сколько удалять мягко удаленные медиа (0 - никогда)
+	RetentionInterval time.Duration          // Период запуска задачи очистки, обязателен при DeletedRetention
+	EventsTopic       string                 // Тема Kafka для событий об изменении медиа
+	OutboxInterval    time.Duration          // Период опроса outbox
+	OutboxBatchSize   int                    // Сколько сообщений outbox отправлять за раз
+	OutboxMaxBackoff  time.Duration          // Максимальная задержка между попытками отправки
+	OutboxRetention   time.Duration          // Через сколько удалять отправленные сообщения outbox (0 - хранить)
+	CommandsTopic     string                 // Тема Kafka с командами RefreshMedia/ImportMedia (пусто - выключено)
+	CommandsGroup     string                 // Группа потребителей команд
+	CommandsDLQTopic  string                 // Тема для команд, которые не удалось выполнить
+	CommandsWorkers   int                    // Сколько команд выполнять одновременно
+	CommandsAttempts  int                    // Сколько раз пытаться выполнить команду
+	FreshnessTTL      time.Duration          // Сколько данные из Кинопоиска считаются актуальными (0 - не обновлять в фоне)
+	ResyncInterval    time.Duration          // Период сверки давно не обновлявшихся медиа с Кинопоиском (0 - выключено)
+	ResyncBatchSize   int                    // Сколько медиа сверять за один запуск
+	KinopoiskGap      time.Duration          // Пауза между фоновыми запросами к Кинопоиску (сверка и обновление устаревших медиа)
+	GatewayToken      string                 // Секрет gateway для метаданных x-user-* (пусто - gRPC порт доступен только gateway)
 }
 
 // LoadConfig загружает конфигурацию из .env файла
@@ -60,20 +92,278 @@ func LoadConfig() (*Config, error) {
 		logBufferSize = 100
 	}
 
+	// Преобразуем LOG_LEVEL в slog.Level, по умолчанию debug
+	logLevel := slog.LevelDebug
+	if value := os.Getenv("LOG_LEVEL"); value != "" {
+		if err := logLevel.UnmarshalText([]byte(value)); err != nil {
+			return nil, fmt.Errorf("invalid LOG_LEVEL value: %w", err)
+		}
+	}
+
+	// ADMIN_ADDR - только loopback адрес: admin сервер не требует аутентификации
+	adminAddr := os.Getenv("ADMIN_ADDR")
+	if adminAddr != "" && !isLoopbackAddr(adminAddr) {
+		return nil, fmt.Errorf("invalid ADMIN_ADDR value %q: admin server has no authentication and must listen on a loopback address", adminAddr)
+	}
+
+	// Преобразуем RPC_DEADLINES вида "*=5s/10s,GetMediasByName=8s/15s"
+	rpcDeadlines, err := parseRPCDeadlines(os.Getenv("RPC_DEADLINES"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RPC_DEADLINES value: %w", err)
+	}
+
+	// Преобразуем DB_AUTO_MIGRATE в bool, по умолчанию выключено
+	dbAutoMigrate := false
+	if value := os.Getenv("DB_AUTO_MIGRATE"); value != "" {
+		if dbAutoMigrate, err = strconv.ParseBool(value); err != nil {
+			return nil, fmt.Errorf("invalid DB_AUTO_MIGRATE value: %w", err)
+		}
+	}
+
+	// LOCAL_SEARCH_MODE по умолчанию - полнотекстовый поиск
+	localSearchMode := os.Getenv("LOCAL_SEARCH_MODE")
+	if localSearchMode == "" {
+		localSearchMode = "fulltext"
+	}
+
+	// TRIGRAM_SIMILARITY_THRESHOLD - число от 0 до 1, 0 означает значение по умолчанию
+	var trigramThreshold float64
+	if value := os.Getenv("TRIGRAM_SIMILARITY_THRESHOLD"); value != "" {
+		trigramThreshold, err = strconv.ParseFloat(value, 64)
+		if err != nil || trigramThreshold < 0 || trigramThreshold > 1 {
+			return nil, fmt.Errorf("invalid TRIGRAM_SIMILARITY_THRESHOLD value: %q", value)
+		}
+	}
+
+	// DELETED_RETENTION и RETENTION_INTERVAL - длительности вида "720h"
+	deletedRetention, err := parseOptionalDuration("DELETED_RETENTION", 0)
+	if err != nil {
+		return nil, err
+	}
+	retentionInterval, err := parseOptionalDuration("RETENTION_INTERVAL", time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	// Без периода запуска задача очистки не работает и удаленные медиа копились бы незаметно
+	if deletedRetention > 0 && retentionInterval == 0 {
+		return nil, fmt.Errorf("invalid RETENTION_INTERVAL value: must be positive when DELETED_RETENTION is set")
+	}
+
+	// MEDIA_EVENTS_TOPIC по умолчанию - media-events
+	eventsTopic := os.Getenv("MEDIA_EVENTS_TOPIC")
+	if eventsTopic == "" {
+		eventsTopic = "media-events"
+	}
+
+	// Параметры отправки outbox
+	outboxInterval, err := parseOptionalDuration("OUTBOX_POLL_INTERVAL", time.Second)
+	if err != nil {
+		return nil, err
+	}
+	if outboxInterval == 0 {
+		return nil, fmt.Errorf("invalid OUTBOX_POLL_INTERVAL value: must be positive")
+	}
+	outboxMaxBackoff, err := parseOptionalDuration("OUTBOX_MAX_BACKOFF", 5*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	outboxBatchSize, err := parseOptionalPositiveInt("OUTBOX_BATCH_SIZE", 100)
+	if err != nil {
+		return nil, err
+	}
+	outboxRetention, err := parseOptionalDuration("OUTBOX_SENT_RETENTION", 24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	// Параметры потребителя команд, группа и dead-letter топик выводятся из имен сервиса и топика
+	commandsTopic := os.Getenv("COMMANDS_TOPIC")
+	commandsGroup := os.Getenv("COMMANDS_GROUP")
+	if commandsGroup == "" {
+		commandsGroup = os.Getenv("SERVICE_NAME") + "-commands"
+	}
+	commandsDLQTopic := os.Getenv("COMMANDS_DLQ_TOPIC")
+	if commandsDLQTopic == "" && commandsTopic != "" {
+		commandsDLQTopic = commandsTopic + ".dlq"
+	}
+	commandsWorkers, err := parseOptionalPositiveInt("COMMANDS_CONCURRENCY", 4)
+	if err != nil {
+		return nil, err
+	}
+	commandsAttempts, err := parseOptionalPositiveInt("COMMANDS_MAX_ATTEMPTS", 3)
+	if err != nil {
+		return nil, err
+	}
+
+	// MEDIA_FRESHNESS_TTL - после этого срока с UpdatedAt медиа обновляется из Кинопоиска в фоне.
+	// Обновление перезаписывает ручные правки, поэтому по умолчанию выключено
+	freshnessTTL, err := parseOptionalDuration("MEDIA_FRESHNESS_TTL", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Параметры фоновой сверки с Кинопоиском
+	resyncInterval, err := parseOptionalDuration("RESYNC_INTERVAL", 0)
+	if err != nil {
+		return nil, err
+	}
+	resyncBatchSize, err := parseOptionalPositiveInt("RESYNC_BATCH_SIZE", 50)
+	if err != nil {
+		return nil, err
+	}
+
+	// KINOPOISK_REQUEST_INTERVAL - общая пауза для всех фоновых запросов к Кинопоиску
+	kinopoiskGap, err := parseOptionalDuration("KINOPOISK_REQUEST_INTERVAL", time.Second)
+	if err != nil {
+		return nil, err
+	}
+
 	// Возвращаем конфигурацию
 	return &Config{
-		KinopoiskAPIKey: os.Getenv("KINOPOISK_API_KEY"),
-		KinopoiskAPIURL: os.Getenv("KINOPOISK_API_URL"),
-		DBHost:          os.Getenv("DB_HOST"),
-		DBPort:          os.Getenv("DB_PORT"),
-		DBUser:          os.Getenv("DB_USER"),
-		DBPassword:      os.Getenv("DB_PASSWORD"),
-		DBName:          os.Getenv("DB_NAME"),
-		DBSSLMode:       os.Getenv("DB_SSLMODE"),
-		KafkaBrokers:    kafkaBrokers,
-		KafkaTopic:      os.Getenv("KAFKA_TOPIC"),
-		GRPCPort:        os.Getenv("GRPC_PORT"),
-		ServiceName:     os.Getenv("SERVICE_NAME"),
-		LogBufferSize:   logBufferSize,
+		KinopoiskAPIKey:   os.Getenv("KINOPOISK_API_KEY"),
+		KinopoiskAPIURL:   os.Getenv("KINOPOISK_API_URL"),
+		DBHost:            os.Getenv("DB_HOST"),
+		DBPort:            os.Getenv("DB_PORT"),
+		DBUser:            os.Getenv("DB_USER"),
+		DBPassword:        os.Getenv("DB_PASSWORD"),
+		DBName:            os.Getenv("DB_NAME"),
+		DBSSLMode:         os.Getenv("DB_SSLMODE"),
+		KafkaBrokers:      kafkaBrokers,
+		KafkaTopic:        os.Getenv("KAFKA_TOPIC"),
+		GRPCPort:          os.Getenv("GRPC_PORT"),
+		ServiceName:       os.Getenv("SERVICE_NAME"),
+		LogBufferSize:     logBufferSize,
+		LogLevel:          logLevel,
+		AdminAddr:         adminAddr,
+		RPCDeadlines:      rpcDeadlines,
+		DBAutoMigrate:     dbAutoMigrate,
+		LocalSearchMode:   localSearchMode,
+		TrigramThreshold:  trigramThreshold,
+		DeletedRetention:  deletedRetention,
+		RetentionInterval: retentionInterval,
+		EventsTopic:       eventsTopic,
+		OutboxInterval:    outboxInterval,
+		OutboxBatchSize:   outboxBatchSize,
+		OutboxMaxBackoff:  outboxMaxBackoff,
+		OutboxRetention:   outboxRetention,
+		CommandsTopic:     commandsTopic,
+		CommandsGroup:     commandsGroup,
+		CommandsDLQTopic:  commandsDLQTopic,
+		CommandsWorkers:   commandsWorkers,
+		CommandsAttempts:  commandsAttempts,
+		FreshnessTTL:      freshnessTTL,
+		ResyncInterval:    resyncInterval,
+		ResyncBatchSize:   resyncBatchSize,
+		KinopoiskGap:      kinopoiskGap,
+		GatewayToken:      os.Getenv("GATEWAY_TOKEN"),
 	}, nil
 }
+
+// parseOptionalDuration читает длительность из переменной окружения или возвращает значение по умолчанию
+func parseOptionalDuration(envVar string, defaultValue time.Duration) (time.Duration, error) {
+	value := os.Getenv(envVar)
+	if value == "" {
+		return defaultValue, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid %s value: %q", envVar, value)
+	}
+	return d, nil
+}
+
+// parseOptionalPositiveInt читает положительное число из переменной окружения или возвращает значение по умолчанию
+func parseOptionalPositiveInt(envVar string, defaultValue int) (int, error) {
+	value := os.Getenv(envVar)
+	if value == "" {
+		return defaultValue, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid %s value: %q", envVar, value)
+	}
+	return n, nil
+}
+
+// parseRPCDeadlines разбирает список "метод=default/max", любая из частей может быть пустой
+func parseRPCDeadlines(value string) (map[string]RPCDeadline, error) {
+	deadlines := make(map[string]RPCDeadline)
+	if strings.TrimSpace(value) == "" {
+		return deadlines, nil
+	}
+
+	for _, item := range strings.Split(value, ",") {
+		method, durations, found := strings.Cut(strings.TrimSpace(item), "=")
+		if !found || method == "" {
+			return nil, fmt.Errorf("expected method=default/max, got %q", item)
+		}
+
+		defaultValue, maxValue, _ := strings.Cut(durations, "/")
+		var deadline RPCDeadline
+		var err error
+		if defaultValue != "" {
+			if deadline.Default, err = time.ParseDuration(defaultValue); err != nil {
+				return nil, fmt.Errorf("invalid default deadline for %s: %w", method, err)
+			}
+		}
+		if maxValue != "" {
+			if deadline.Max, err = time.ParseDuration(maxValue); err != nil {
+				return nil, fmt.Errorf("invalid max deadline for %s: %w", method, err)
+			}
+		}
+		if deadline.Max > 0 && deadline.Default > deadline.Max {
+			return nil, fmt.Errorf("default deadline for %s exceeds max", method)
+		}
+		deadlines[method] = deadline
+	}
+	return deadlines, nil
+}
+
+// isLoopbackAddr проверяет, что адрес вида host:port слушает только loopback интерфейс
+func isLoopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// Public возвращает параметры конфигурации, которые можно показывать в admin API.
+// Используется явный список: адреса, учетные данные и ключи сюда не попадают,
+// новые параметры нужно добавлять осознанно
+func (c *Config) Public() map[string]any {
+	deadlines := make(map[string]string, len(c.RPCDeadlines))
+	for method, deadline := range c.RPCDeadlines {
+		deadlines[method] = deadline.Default.String() + "/" + deadline.Max.String()
+	}
+	return map[string]any{
+		"service_name":               c.ServiceName,
+		"grpc_port":                  c.GRPCPort,
+		"log_buffer_size":            c.LogBufferSize,
+		"log_level":                  c.LogLevel.String(),
+		"rpc_deadlines":              deadlines,
+		"db_auto_migrate":            c.DBAutoMigrate,
+		"local_search_mode":          c.LocalSearchMode,
+		"trigram_threshold":          c.TrigramThreshold,
+		"deleted_retention":          c.DeletedRetention.String(),
+		"retention_interval":         c.RetentionInterval.String(),
+		"events_topic":               c.EventsTopic,
+		"outbox_interval":            c.OutboxInterval.String(),
+		"outbox_batch_size":          c.OutboxBatchSize,
+		"outbox_max_backoff":         c.OutboxMaxBackoff.String(),
+		"outbox_sent_retention":      c.OutboxRetention.String(),
+		"commands_topic":             c.CommandsTopic,
+		"commands_group":             c.CommandsGroup,
+		"commands_dlq_topic":         c.CommandsDLQTopic,
+		"commands_workers":           c.CommandsWorkers,
+		"commands_attempts":          c.CommandsAttempts,
+		"freshness_ttl":              c.FreshnessTTL.String(),
+		"resync_interval":            c.ResyncInterval.String(),
+		"resync_batch_size":          c.ResyncBatchSize,
+		"kinopoisk_request_interval": c.KinopoiskGap.String(),
+	}
+}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIsLoopbackAddr(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"127.0.0.1:6060", true},
+		{"127.1.2.3:6060", true},
+		{"[::1]:6060", true},
+		{"localhost:6060", true},
+		{":6060", false},
+		{"0.0.0.0:6060", false},
+		{"[::]:6060", false},
+		{"10.0.0.5:6060", false},
+		{"admin.example.com:6060", false},
+		{"127.0.0.1", false},
+	}
+
+	for _, tt := range tests {
+		if got := isLoopbackAddr(tt.addr); got != tt.want {
+			t.Errorf("isLoopbackAddr(%q) = %v, want %v", tt.addr, got, tt.want)
+		}
+	}
+}
+
+func TestPublicOmitsSecrets(t *testing.T) {
+	cfg := &Config{
+		KinopoiskAPIKey: "kp-secret",
+		KinopoiskAPIURL: "https://kp.internal",
+		DBHost:          "db.internal",
+		DBUser:          "media-user",
+		DBPassword:      "db-secret",
+		GatewayToken:    "gateway-secret",
+		KafkaBrokers:    []string{"kafka.internal:9092"},
+		ServiceName:     "media",
+		RPCDeadlines:    map[string]RPCDeadline{"*": {}},
+	}
+
+	data, err := json.Marshal(cfg.Public())
+	if err != nil {
+		t.Fatalf("failed to encode config: %v", err)
+	}
+	for _, secret := range []string{"kp-secret", "kp.internal", "db.internal", "media-user", "db-secret", "gateway-secret", "kafka.internal"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("Public() exposes %q: %s", secret, data)
+		}
+	}
+	if !strings.Contains(string(data), `"service_name":"media"`) {
+		t.Errorf("Public() misses service_name: %s", data)
+	}
+}
+
+func TestParseRPCDeadlines(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    map[string]RPCDeadline
+		wantErr bool
+	}{
+		{name: "empty", value: "", want: map[string]RPCDeadline{}},
+		{name: "blank", value: "  ", want: map[string]RPCDeadline{}},
+		{
+			name:  "default and max",
+			value: "*=5s/30s,GetMediasByName=8s/15s",
+			want: map[string]RPCDeadline{
+				"*":               {Default: 5 * time.Second, Max: 30 * time.Second},
+				"GetMediasByName": {Default: 8 * time.Second, Max: 15 * time.Second},
+			},
+		},
+		{name: "default only", value: "ExportMedia=2h", want: map[string]RPCDeadline{"ExportMedia": {Default: 2 * time.Hour}}},
+		{name: "max only", value: "*=/10s", want: map[string]RPCDeadline{"*": {Max: 10 * time.Second}}},
+		{name: "spaces around items", value: " *=1s , SaveMedia=2s ", want: map[string]RPCDeadline{"*": {Default: time.Second}, "SaveMedia": {Default: 2 * time.Second}}},
+		{name: "missing equals", value: "SaveMedia", wantErr: true},
+		{name: "missing method", value: "=5s", wantErr: true},
+		{name: "invalid default", value: "*=soon", wantErr: true},
+		{name: "invalid max", value: "*=1s/later", wantErr: true},
+		{name: "default exceeds max", value: "*=30s/5s", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseRPCDeadlines(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseRPCDeadlines(%q) = %v, want error", tt.value, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseRPCDeadlines(%q) failed: %v", tt.value, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseRPCDeadlines(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+// Package events описывает доменные события об изменении медиа,
+// которые публикуются в Kafka через outbox.
+//
+// Событие кодируется в JSON, ключ сообщения - ID медиа, поэтому события
+// одного медиа попадают в одну партицию и читаются по порядку. Тип события
+// передается в заголовке event_type и в поле type, версия схемы - в поле
+// schema_version. Несовместимые изменения схемы увеличивают SchemaVersion.
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SchemaVersion - текущая версия схемы событий
+const SchemaVersion = 1
+
+// ContentType - формат тела сообщения
+const ContentType = "application/json"
+
+// Type - тип доменного события
+type Type string
+
+const (
+	MediaCreated  Type = "MediaCreated"
+	MediaUpdated  Type = "MediaUpdated"
+	MediaDeleted  Type = "MediaDeleted"
+	MediaRestored Type = "MediaRestored"
+)
+
+// FieldChange - значение поля до и после изменения
+type FieldChange struct {
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// MediaSnapshot - состояние медиа после изменения
+type MediaSnapshot struct {
+	ID          int64     `json:"id"`
+	KinopoiskID int64     `json:"kinopoisk_id"`
+	Type        string    `json:"type"`
+	NameEn      string    `json:"name_en"`
+	NameRu      string    `json:"name_ru"`
+	Description string    `json:"description"`
+	Year        string    `json:"year"`
+	Poster      string    `json:"poster"`
+	Countries   string    `json:"countries"`
+	Genres      string    `json:"genres"`
+	Version     int64     `json:"version"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// MediaEvent - доменное событие об изменении медиа
+type MediaEvent struct {
+	SchemaVersion int                    `json:"schema_version"`
+	EventID       string                 `json:"event_id"` // Для дедупликации при повторной доставке
+	Type          Type                   `json:"type"`
+	MediaID       int64                  `json:"media_id"`
+	Version       int64                  `json:"version"` // Версия медиа после изменения
+	OccurredAt    time.Time              `json:"occurred_at"`
+	Actor         string                 `json:"actor"` // Пользователь или kinopoisk-sync
+	RequestID     string                 `json:"request_id,omitempty"`
+	Purged        bool                   `json:"purged,omitempty"` // Для MediaDeleted: запись удалена окончательно
+	ChangedFields []string               `json:"changed_fields"`
+	Changes       map[string]FieldChange `json:"changes"`
+	Media         *MediaSnapshot         `json:"media,omitempty"` // Нет у MediaDeleted
+}
+
+// NewMediaEvent создает событие с новым ID и списком измененных полей
+func NewMediaEvent(eventType Type, mediaID, version int64, changes map[string]FieldChange) *MediaEvent {
+	changedFields := make([]string, 0, len(changes))
+	for field := range changes {
+		changedFields = append(changedFields, field)
+	}
+	sort.Strings(changedFields)
+
+	return &MediaEvent{
+		SchemaVersion: SchemaVersion,
+		EventID:       uuid.New().String(),
+		Type:          eventType,
+		MediaID:       mediaID,
+		Version:       version,
+		OccurredAt:    time.Now().UTC(),
+		ChangedFields: changedFields,
+		Changes:       changes,
+	}
+}
+
+// Encode кодирует событие в тело сообщения
+func (e *MediaEvent) Encode() ([]byte, error) {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s event: %w", e.Type, err)
+	}
+	return payload, nil
+}
@@ -0,0 +1,179 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/watchlist-kata/media/internal/events"
+	"github.com/watchlist-kata/media/internal/repository"
+)
+
+const (
+	// outboxLease - на сколько откладываются забранные сообщения, пока идет отправка.
+	// Если реплика упадет до отметки об отправке, сообщения будут отправлены повторно
+	outboxLease = 30 * time.Second
+	// outboxBaseBackoff - задержка перед первой повторной попыткой
+	outboxBaseBackoff = time.Second
+	// outboxCleanupInterval - как часто удалять отправленные сообщения старше срока хранения
+	outboxCleanupInterval = time.Hour
+	// outboxCleanupBatch - сколько отправленных сообщений удалять одним запросом
+	outboxCleanupBatch = 1000
+)
+
+// OutboxRelay периодически публикует сообщения из outbox в Kafka.
+// Доставка at-least-once: сообщение отмечается отправленным только после подтверждения брокера
+type OutboxRelay struct {
+	repo       repository.Repository
+	producer   sarama.SyncProducer
+	topic      string
+	logger     *slog.Logger
+	interval   time.Duration
+	batchSize  int
+	maxBackoff time.Duration
+	retention  time.Duration // Срок хранения отправленных сообщений (0 - не удалять)
+}
+
+// NewOutboxRelay создает новый OutboxRelay
+func NewOutboxRelay(repo repository.Repository, producer sarama.SyncProducer, topic string, logger *slog.Logger, interval time.Duration, batchSize int, maxBackoff, retention time.Duration) *OutboxRelay {
+	return &OutboxRelay{
+		repo:       repo,
+		producer:   producer,
+		topic:      topic,
+		logger:     logger,
+		interval:   interval,
+		batchSize:  batchSize,
+		maxBackoff: maxBackoff,
+		retention:  retention,
+	}
+}
+
+// Run публикует сообщения каждые interval до отмены контекста.
+// После полностью отправленной полной пачки следующая забирается сразу, не дожидаясь тикера
+func (j *OutboxRelay) Run(ctx context.Context) {
+	j.logger.Info("Starting outbox relay", "topic", j.topic, "interval", j.interval, "batch_size", j.batchSize, "retention", j.retention)
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	var lastCleanup time.Time
+	for {
+		for more := true; more && ctx.Err() == nil; {
+			more = j.runOnce(ctx)
+		}
+		if j.retention > 0 && time.Since(lastCleanup) >= outboxCleanupInterval && ctx.Err() == nil {
+			j.cleanup(ctx)
+			lastCleanup = time.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			j.logger.Info("Outbox relay stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runOnce отправляет одну пачку сообщений и сообщает, могут ли остаться готовые к отправке
+func (j *OutboxRelay) runOnce(ctx context.Context) bool {
+	messages, err := j.repo.ClaimOutbox(ctx, j.batchSize, outboxLease)
+	if err != nil {
+		if ctx.Err() == nil {
+			j.logger.ErrorContext(ctx, "Outbox relay failed to claim messages", "error", err)
+		}
+		return false
+	}
+	if len(messages) == 0 {
+		return false
+	}
+
+	producerMessages := make([]*sarama.ProducerMessage, len(messages))
+	for i, m := range messages {
+		producerMessages[i] = &sarama.ProducerMessage{
+			Topic: j.topic,
+			Key:   sarama.StringEncoder(m.MessageKey),
+			Value: sarama.StringEncoder(m.Payload),
+			Headers: []sarama.RecordHeader{
+				{Key: []byte("event_type"), Value: []byte(m.EventType)},
+				{Key: []byte("content-type"), Value: []byte(events.ContentType)},
+			},
+			Metadata: i,
+		}
+	}
+
+	failed := make(map[int]error)
+	if err := j.producer.SendMessages(producerMessages); err != nil {
+		var producerErrors sarama.ProducerErrors
+		if errors.As(err, &producerErrors) {
+			for _, pe := range producerErrors {
+				if i, ok := pe.Msg.Metadata.(int); ok {
+					failed[i] = pe.Err
+				}
+			}
+		} else {
+			for i := range messages {
+				failed[i] = err
+			}
+		}
+	}
+
+	sent := make([]int64, 0, len(messages))
+	for i, m := range messages {
+		sendErr, isFailed := failed[i]
+		if !isFailed {
+			sent = append(sent, m.ID)
+			continue
+		}
+		nextAttemptAt := time.Now().Add(j.backoff(m.Attempts))
+		j.logger.WarnContext(ctx, "Failed to publish outbox message", "id", m.ID, "event_type", m.EventType, "attempts", m.Attempts, "next_attempt_at", nextAttemptAt, "error", sendErr)
+		if err := j.repo.MarkOutboxFailed(ctx, m.ID, sendErr, nextAttemptAt); err != nil {
+			j.logger.ErrorContext(ctx, "Outbox relay failed to reschedule message", "id", m.ID, "error", err)
+		}
+	}
+
+	if err := j.repo.MarkOutboxSent(ctx, sent); err != nil {
+		// Сообщения будут отправлены повторно после истечения outboxLease
+		j.logger.ErrorContext(ctx, "Outbox relay failed to mark messages as sent", "count", len(sent), "error", err)
+	}
+	if len(sent) > 0 {
+		j.logger.DebugContext(ctx, "Outbox messages published", "count", len(sent), "failed", len(failed))
+	}
+	return len(messages) == j.batchSize && len(failed) == 0
+}
+
+// cleanup удаляет отправленные сообщения старше срока хранения небольшими пачками
+func (j *OutboxRelay) cleanup(ctx context.Context) {
+	cutoff := time.Now().Add(-j.retention)
+	var total int64
+	for {
+		deleted, err := j.repo.DeleteSentOutbox(ctx, cutoff, outboxCleanupBatch)
+		if err != nil {
+			if ctx.Err() == nil {
+				j.logger.ErrorContext(ctx, "Outbox relay failed to delete sent messages", "cutoff", cutoff, "error", err)
+			}
+			return
+		}
+		total += deleted
+		if deleted < outboxCleanupBatch {
+			break
+		}
+	}
+	if total > 0 {
+		j.logger.InfoContext(ctx, "Outbox relay deleted sent messages", "count", total, "cutoff", cutoff)
+	}
+}
+
+// backoff возвращает экспоненциальную задержку перед следующей попыткой, не больше maxBackoff
+func (j *OutboxRelay) backoff(attempts int) time.Duration {
+	delay := outboxBaseBackoff
+	for i := 1; i < attempts && delay < j.maxBackoff; i++ {
+		delay *= 2
+	}
+	if j.maxBackoff > 0 && delay > j.maxBackoff {
+		delay = j.maxBackoff
+	}
+	return delay
+}
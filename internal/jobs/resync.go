@@ -0,0 +1,127 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/watchlist-kata/media/internal/audit"
+	"github.com/watchlist-kata/media/internal/kinopoisk"
+	"github.com/watchlist-kata/media/internal/repository"
+	"github.com/watchlist-kata/protos/media"
+)
+
+// resyncLockName - имя advisory-блокировки, которую держит реплика, выполняющая сверку
+const resyncLockName = "media-resync-job"
+
+// Refresher обновляет сохраненное медиа данными из Кинопоиска
+type Refresher interface {
+	RefreshMedia(ctx context.Context, kinopoiskID int64) (*media.Media, error)
+}
+
+// ResyncJob периодически сверяет с Кинопоиском медиа, которые дольше всех не обновлялись.
+// Одновременно задача выполняется только на одной реплике
+type ResyncJob struct {
+	repo      repository.Repository
+	refresher Refresher
+	limiter   *kinopoisk.Limiter // Общий с фоновым обновлением медиа лимит запросов к Кинопоиску
+	logger    *slog.Logger
+	interval  time.Duration
+	batchSize int
+}
+
+// NewResyncJob создает новый ResyncJob
+func NewResyncJob(repo repository.Repository, refresher Refresher, limiter *kinopoisk.Limiter, logger *slog.Logger, interval time.Duration, batchSize int) *ResyncJob {
+	return &ResyncJob{
+		repo:      repo,
+		refresher: refresher,
+		limiter:   limiter,
+		logger:    logger,
+		interval:  interval,
+		batchSize: batchSize,
+	}
+}
+
+// Run выполняет сверку каждые interval до отмены контекста
+func (j *ResyncJob) Run(ctx context.Context) {
+	j.logger.Info("Starting resync job", "interval", j.interval, "batch_size", j.batchSize)
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		j.runOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			j.logger.Info("Resync job stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runOnce сверяет одну партию медиа, если блокировку не держит другая реплика
+func (j *ResyncJob) runOnce(ctx context.Context) {
+	unlock, acquired, err := j.repo.TryAdvisoryLock(ctx, resyncLockName)
+	if err != nil {
+		j.logger.ErrorContext(ctx, "Resync job failed to acquire lock", "error", err)
+		return
+	}
+	if !acquired {
+		j.logger.DebugContext(ctx, "Resync job is running on another replica")
+		return
+	}
+	defer unlock()
+
+	medias, err := j.repo.ListStaleMedia(ctx, j.batchSize)
+	if err != nil {
+		j.logger.ErrorContext(ctx, "Resync job failed to list media", "error", err)
+		return
+	}
+
+	// Все изменения партии попадают в историю с одним ID запроса
+	ctx = audit.WithActor(ctx, audit.ActorResync)
+	ctx = audit.WithRequestID(ctx, "resync:"+time.Now().UTC().Format(time.RFC3339))
+	counts := make(map[repository.SyncStatus]int)
+	for i, m := range medias {
+		if err := j.limiter.Wait(ctx); err != nil {
+			return
+		}
+
+		status, syncErr := j.resync(ctx, m)
+		if ctx.Err() != nil {
+			return
+		}
+		if errors.Is(syncErr, kinopoisk.ErrRateLimited) {
+			// Медиа не сверялось, результат не записываем, чтобы оно осталось первым в очереди
+			j.logger.WarnContext(ctx, "Kinopoisk rate limit reached, postponing the rest of the batch", "remaining", len(medias)-i)
+			break
+		}
+		counts[status]++
+		if err := j.repo.RecordSyncResult(ctx, m.Id, status, syncErr); err != nil {
+			j.logger.ErrorContext(ctx, "Resync job failed to record result", "id", m.Id, "error", err)
+		}
+	}
+
+	if len(medias) > 0 {
+		j.logger.InfoContext(ctx, "Resync job finished batch", "selected", len(medias),
+			"updated", counts[repository.SyncStatusUpdated],
+			"unchanged", counts[repository.SyncStatusUnchanged],
+			"failed", counts[repository.SyncStatusFailed])
+	}
+}
+
+// resync обновляет одно медиа через RefreshMedia и определяет результат по версии записи
+func (j *ResyncJob) resync(ctx context.Context, m *media.Media) (repository.SyncStatus, error) {
+	refreshed, err := j.refresher.RefreshMedia(ctx, m.KinopoiskId)
+	if err != nil {
+		j.logger.WarnContext(ctx, "Resync job failed to refresh media", "id", m.Id, "kinopoiskID", m.KinopoiskId, "error", err)
+		return repository.SyncStatusFailed, err
+	}
+	if refreshed.Version != m.Version {
+		return repository.SyncStatusUpdated, nil
+	}
+	return repository.SyncStatusUnchanged, nil
+}
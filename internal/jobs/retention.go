@@ -0,0 +1,61 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/watchlist-kata/media/internal/audit"
+	"github.com/watchlist-kata/media/internal/repository"
+)
+
+// RetentionJob периодически окончательно удаляет медиа,
+// мягко удаленные раньше, чем maxAge назад
+type RetentionJob struct {
+	repo     repository.Repository
+	logger   *slog.Logger
+	interval time.Duration
+	maxAge   time.Duration
+}
+
+// NewRetentionJob создает новый RetentionJob
+func NewRetentionJob(repo repository.Repository, logger *slog.Logger, interval, maxAge time.Duration) *RetentionJob {
+	return &RetentionJob{
+		repo:     repo,
+		logger:   logger,
+		interval: interval,
+		maxAge:   maxAge,
+	}
+}
+
+// Run выполняет очистку каждые interval до отмены контекста
+func (j *RetentionJob) Run(ctx context.Context) {
+	j.logger.Info("Starting retention job", "interval", j.interval, "max_age", j.maxAge)
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		j.runOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			j.logger.Info("Retention job stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runOnce удаляет устаревшие мягко удаленные записи
+func (j *RetentionJob) runOnce(ctx context.Context) {
+	cutoff := time.Now().Add(-j.maxAge)
+	purged, err := j.repo.PurgeDeletedBefore(audit.WithActor(ctx, audit.ActorRetention), cutoff)
+	if err != nil {
+		j.logger.ErrorContext(ctx, "Retention job failed", "cutoff", cutoff, "error", err)
+		return
+	}
+	if purged > 0 {
+		j.logger.InfoContext(ctx, "Retention job purged deleted media", "count", purged, "cutoff", cutoff)
+	}
+}
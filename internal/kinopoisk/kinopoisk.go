@@ -3,16 +3,28 @@ package kinopoisk
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/watchlist-kata/protos/media"
 )
 
+var (
+	// ErrFilmNotFound - фильм с таким ID не найден в Кинопоиске
+	ErrFilmNotFound = errors.New("film not found in Kinopoisk")
+	// ErrRateLimited - превышен лимит запросов к API Кинопоиска
+	ErrRateLimited = errors.New("Kinopoisk rate limit exceeded")
+)
+
+// filmURL - адрес API для получения фильма по ID
+const filmURL = "https://kinopoiskapiunofficial.tech/api/v2.2/films/"
+
 // KPClient представляет собой клиента для работы с API Кинопоиска
 type KPClient struct {
 	apiKey string
@@ -92,12 +104,101 @@ func (c *KPClient) SearchByKeyword(ctx context.Context, keyword string) ([]*medi
 			Poster:      film.PosterUrl,
 			Countries:   countriesToString(film.Countries),
 			Genres:      genresToString(film.Genres),
+			CountryList: countriesToList(film.Countries),
+			GenreList:   genresToList(film.Genres),
 		})
 	}
 
 	return medias, nil
 }
 
+// GetByID получает фильм по ID Кинопоиска
+func (c *KPClient) GetByID(ctx context.Context, kinopoiskID int64) (*media.Media, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", filmURL+strconv.FormatInt(kinopoiskID, 10), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("X-API-KEY", c.apiKey)
+	req.Header.Set("accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request for kinopoisk_id %d: %w", kinopoiskID, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("kinopoisk_id %d: %w", kinopoiskID, ErrFilmNotFound)
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, fmt.Errorf("kinopoisk_id %d: %w", kinopoiskID, ErrRateLimited)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get Kinopoisk film %d: status code %d", kinopoiskID, resp.StatusCode)
+	}
+
+	var details filmDetails
+	if err := json.NewDecoder(resp.Body).Decode(&details); err != nil {
+		return nil, fmt.Errorf("failed to parse response JSON: %w", err)
+	}
+
+	c.logger.DebugContext(ctx, "Kinopoisk film received", "kinopoisk_id", kinopoiskID, "name_ru", details.NameRu)
+
+	return &media.Media{
+		KinopoiskId: details.KinopoiskId,
+		NameRu:      details.NameRu,
+		NameEn:      details.nameEn(),
+		Year:        details.year(),
+		Description: details.Description,
+		Type:        details.Type,
+		Poster:      details.PosterUrl,
+		Countries:   countriesToString(details.Countries),
+		Genres:      genresToString(details.Genres),
+		CountryList: countriesToList(details.Countries),
+		GenreList:   genresToList(details.Genres),
+	}, nil
+}
+
+// filmDetails - ответ API v2.2 с данными одного фильма
+type filmDetails struct {
+	KinopoiskId  int64  `json:"kinopoiskId"`
+	NameRu       string `json:"nameRu"`
+	NameEn       string `json:"nameEn"`
+	NameOriginal string `json:"nameOriginal"`
+	Type         string `json:"type"`
+	Year         int    `json:"year"`
+	StartYear    int    `json:"startYear"`
+	EndYear      int    `json:"endYear"`
+	Serial       bool   `json:"serial"`
+	Description  string `json:"description"`
+	PosterUrl    string `json:"posterUrl"`
+	Countries    []country
+	Genres       []genre
+}
+
+// nameEn возвращает английское название, для неанглоязычных фильмов его может не быть
+func (f *filmDetails) nameEn() string {
+	if f.NameEn != "" {
+		return f.NameEn
+	}
+	return f.NameOriginal
+}
+
+// year возвращает год выпуска в формате поиска: YYYY или YYYY-YYYY для завершенных сериалов
+func (f *filmDetails) year() string {
+	if f.Serial && f.StartYear > 0 && f.EndYear > 0 && f.EndYear != f.StartYear {
+		return fmt.Sprintf("%d-%d", f.StartYear, f.EndYear)
+	}
+	if f.Year > 0 {
+		return strconv.Itoa(f.Year)
+	}
+	if f.StartYear > 0 {
+		return strconv.Itoa(f.StartYear)
+	}
+	return ""
+}
+
 type SearchFilmsResult struct {
 	Total int `json:"total"`
 	Items []film
@@ -144,3 +245,19 @@ func genresToString(genres []genre) string {
 	}
 	return result
 }
+
+func countriesToList(countries []country) []string {
+	result := make([]string, 0, len(countries))
+	for _, c := range countries {
+		result = append(result, c.Country)
+	}
+	return result
+}
+
+func genresToList(genres []genre) []string {
+	result := make([]string, 0, len(genres))
+	for _, g := range genres {
+		result = append(result, g.Genre)
+	}
+	return result
+}
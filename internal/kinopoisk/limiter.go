@@ -0,0 +1,50 @@
+package kinopoisk
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limiter выдерживает паузу между фоновыми запросами к Кинопоиску.
+// Один Limiter делят все фоновые задачи, чтобы вместе они не превышали лимит API
+type Limiter struct {
+	interval time.Duration
+	mu       sync.Mutex
+	next     time.Time // Когда можно выполнить следующий запрос
+}
+
+// NewLimiter создает Limiter, пропускающий не больше одного запроса за interval.
+// При interval <= 0, как и у nil Limiter, ограничения нет
+func NewLimiter(interval time.Duration) *Limiter {
+	return &Limiter{interval: interval}
+}
+
+// Wait ждет своей очереди на запрос или отмены контекста
+func (l *Limiter) Wait(ctx context.Context) error {
+	if l == nil || l.interval <= 0 {
+		return ctx.Err()
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	at := l.next
+	if at.Before(now) {
+		at = now
+	}
+	l.next = at.Add(l.interval)
+	l.mu.Unlock()
+
+	delay := time.Until(at)
+	if delay <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
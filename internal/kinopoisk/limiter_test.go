@@ -0,0 +1,53 @@
+package kinopoisk
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLimiterWait(t *testing.T) {
+	tests := []struct {
+		name     string
+		limiter  *Limiter
+		calls    int
+		minTotal time.Duration
+	}{
+		{name: "nil limiter", limiter: nil, calls: 3},
+		{name: "no interval", limiter: NewLimiter(0), calls: 3},
+		{name: "first call is not delayed", limiter: NewLimiter(time.Hour), calls: 1},
+		{name: "calls are spaced", limiter: NewLimiter(20 * time.Millisecond), calls: 3, minTotal: 40 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+			for i := 0; i < tt.calls; i++ {
+				if err := tt.limiter.Wait(context.Background()); err != nil {
+					t.Fatalf("Wait failed: %v", err)
+				}
+			}
+			elapsed := time.Since(start)
+			if elapsed < tt.minTotal {
+				t.Errorf("%d calls took %v, want at least %v", tt.calls, elapsed, tt.minTotal)
+			}
+			if elapsed > tt.minTotal+time.Second {
+				t.Errorf("%d calls took %v, want about %v", tt.calls, elapsed, tt.minTotal)
+			}
+		})
+	}
+}
+
+func TestLimiterWaitCanceled(t *testing.T) {
+	limiter := NewLimiter(time.Hour)
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("first Wait failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Wait error = %v, want context.DeadlineExceeded", err)
+	}
+}
@@ -0,0 +1,102 @@
+package migrations
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoad(t *testing.T) {
+	file := func(content string) *fstest.MapFile { return &fstest.MapFile{Data: []byte(content)} }
+
+	tests := []struct {
+		name     string
+		files    fstest.MapFS
+		versions []int64
+		wantErr  string
+	}{
+		{
+			name: "sorted by version",
+			files: fstest.MapFS{
+				"sql/0010_ten.up.sql":   file("CREATE TABLE ten ();"),
+				"sql/0010_ten.down.sql": file("DROP TABLE ten;"),
+				"sql/0002_two.up.sql":   file("CREATE TABLE two ();"),
+				"sql/0002_two.down.sql": file("DROP TABLE two;"),
+				"sql/0001_one.up.sql":   file("CREATE TABLE one ();"),
+				"sql/0001_one.down.sql": file("DROP TABLE one;"),
+			},
+			versions: []int64{1, 2, 10},
+		},
+		{name: "empty directory", files: fstest.MapFS{"sql": &fstest.MapFile{Mode: fs.ModeDir | 0o755}}, versions: []int64{}},
+		{name: "missing directory", files: fstest.MapFS{}, wantErr: "sql"},
+		{
+			name:    "missing down file",
+			files:   fstest.MapFS{"sql/0001_one.up.sql": file("CREATE TABLE one ();")},
+			wantErr: "must have both up and down files",
+		},
+		{
+			name:    "empty up file",
+			files:   fstest.MapFS{"sql/0001_one.up.sql": file(""), "sql/0001_one.down.sql": file("DROP TABLE one;")},
+			wantErr: "must have both up and down files",
+		},
+		{
+			name:    "unknown direction",
+			files:   fstest.MapFS{"sql/0001_one.sideways.sql": file("SELECT 1;")},
+			wantErr: "invalid migration file name",
+		},
+		{
+			name:    "no title",
+			files:   fstest.MapFS{"sql/0001.up.sql": file("SELECT 1;")},
+			wantErr: "invalid migration file name",
+		},
+		{
+			name:    "non-numeric version",
+			files:   fstest.MapFS{"sql/first_one.up.sql": file("SELECT 1;")},
+			wantErr: "invalid migration version",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrations, err := load(tt.files)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("load() error = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("load() failed: %v", err)
+			}
+
+			versions := make([]int64, 0, len(migrations))
+			for _, m := range migrations {
+				versions = append(versions, m.Version)
+			}
+			if len(versions) != len(tt.versions) {
+				t.Fatalf("versions = %v, want %v", versions, tt.versions)
+			}
+			for i := range versions {
+				if versions[i] != tt.versions[i] {
+					t.Fatalf("versions = %v, want %v", versions, tt.versions)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadEmbedded(t *testing.T) {
+	migrations, err := load(files)
+	if err != nil {
+		t.Fatalf("embedded migrations are invalid: %v", err)
+	}
+	for i, m := range migrations {
+		if want := int64(i + 1); m.Version != want {
+			t.Errorf("migration %s has version %d, want %d: versions must have no gaps", m.Name, m.Version, want)
+		}
+	}
+	if first := migrations[0]; first.Name != "create_media" || !strings.Contains(first.Up, "CREATE TABLE") {
+		t.Errorf("first migration = %s, want create_media", first.Name)
+	}
+}
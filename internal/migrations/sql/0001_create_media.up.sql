@@ -0,0 +1,15 @@
+-- Базовая схема таблицы media. IF NOT EXISTS позволяет принять уже существующую таблицу
+CREATE TABLE IF NOT EXISTS media (
+    id           BIGSERIAL PRIMARY KEY,
+    kinopoisk_id BIGINT       NOT NULL UNIQUE,
+    type         VARCHAR(20),
+    name_en      VARCHAR(255),
+    name_ru      VARCHAR(255),
+    description  TEXT,
+    year         VARCHAR(4),
+    poster       VARCHAR(255),
+    countries    VARCHAR(255),
+    genres       VARCHAR(255),
+    created_at   TIMESTAMPTZ  DEFAULT CURRENT_TIMESTAMP,
+    updated_at   TIMESTAMPTZ  DEFAULT CURRENT_TIMESTAMP
+);
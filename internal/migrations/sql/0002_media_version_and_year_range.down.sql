@@ -0,0 +1,3 @@
+ALTER TABLE media DROP COLUMN IF EXISTS version;
+
+ALTER TABLE media ALTER COLUMN year TYPE VARCHAR(4) USING left(year, 4);
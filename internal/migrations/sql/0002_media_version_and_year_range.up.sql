@@ -0,0 +1,5 @@
+-- Год сериала может быть диапазоном "2010-2015"
+ALTER TABLE media ALTER COLUMN year TYPE VARCHAR(9);
+
+-- Версия для оптимистичной блокировки
+ALTER TABLE media ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
@@ -0,0 +1,7 @@
+ALTER TABLE media ALTER COLUMN countries TYPE VARCHAR(255) USING left(countries, 255);
+ALTER TABLE media ALTER COLUMN genres TYPE VARCHAR(255) USING left(genres, 255);
+
+DROP TABLE IF EXISTS media_countries;
+DROP TABLE IF EXISTS media_genres;
+DROP TABLE IF EXISTS countries;
+DROP TABLE IF EXISTS genres;
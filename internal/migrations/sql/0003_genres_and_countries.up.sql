@@ -0,0 +1,62 @@
+-- Справочники жанров и стран
+CREATE TABLE IF NOT EXISTS genres (
+    id   BIGSERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS countries (
+    id   BIGSERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE
+);
+
+-- Связи многие-ко-многим, position сохраняет порядок Кинопоиска
+CREATE TABLE IF NOT EXISTS media_genres (
+    media_id BIGINT  NOT NULL REFERENCES media (id) ON DELETE CASCADE,
+    genre_id BIGINT  NOT NULL REFERENCES genres (id) ON DELETE CASCADE,
+    position INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (media_id, genre_id)
+);
+CREATE INDEX IF NOT EXISTS idx_media_genres_genre_id ON media_genres (genre_id);
+
+CREATE TABLE IF NOT EXISTS media_countries (
+    media_id   BIGINT  NOT NULL REFERENCES media (id) ON DELETE CASCADE,
+    country_id BIGINT  NOT NULL REFERENCES countries (id) ON DELETE CASCADE,
+    position   INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (media_id, country_id)
+);
+CREATE INDEX IF NOT EXISTS idx_media_countries_country_id ON media_countries (country_id);
+
+-- Переносим существующие строки "Драма, Комедия" в справочники
+INSERT INTO genres (name)
+SELECT DISTINCT trim(g.name)
+FROM media m
+CROSS JOIN LATERAL unnest(string_to_array(m.genres, ',')) AS g(name)
+WHERE trim(g.name) <> ''
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO media_genres (media_id, genre_id, position)
+SELECT m.id, gr.id, min(g.ord) - 1
+FROM media m
+CROSS JOIN LATERAL unnest(string_to_array(m.genres, ',')) WITH ORDINALITY AS g(name, ord)
+JOIN genres gr ON gr.name = trim(g.name)
+GROUP BY m.id, gr.id
+ON CONFLICT DO NOTHING;
+
+INSERT INTO countries (name)
+SELECT DISTINCT trim(c.name)
+FROM media m
+CROSS JOIN LATERAL unnest(string_to_array(m.countries, ',')) AS c(name)
+WHERE trim(c.name) <> ''
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO media_countries (media_id, country_id, position)
+SELECT m.id, co.id, min(c.ord) - 1
+FROM media m
+CROSS JOIN LATERAL unnest(string_to_array(m.countries, ',')) WITH ORDINALITY AS c(name, ord)
+JOIN countries co ON co.name = trim(c.name)
+GROUP BY m.id, co.id
+ON CONFLICT DO NOTHING;
+
+-- Старые строковые поля остаются для старых клиентов и больше не обрезаются
+ALTER TABLE media ALTER COLUMN genres TYPE TEXT;
+ALTER TABLE media ALTER COLUMN countries TYPE TEXT;
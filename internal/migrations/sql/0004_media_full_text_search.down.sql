@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_media_search_vector;
+
+ALTER TABLE media DROP COLUMN IF EXISTS search_vector;
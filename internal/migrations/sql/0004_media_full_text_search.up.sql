@@ -0,0 +1,9 @@
+-- Полнотекстовый поиск по названиям (вес A) и описанию (вес C/D) с русской и английской морфологией
+ALTER TABLE media ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('russian'::regconfig, coalesce(name_ru, '')), 'A') ||
+    setweight(to_tsvector('english'::regconfig, coalesce(name_en, '')), 'A') ||
+    setweight(to_tsvector('russian'::regconfig, coalesce(description, '')), 'C') ||
+    setweight(to_tsvector('english'::regconfig, coalesce(description, '')), 'D')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_media_search_vector ON media USING GIN (search_vector);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_media_name_en_trgm;
+DROP INDEX IF EXISTS idx_media_name_ru_trgm;
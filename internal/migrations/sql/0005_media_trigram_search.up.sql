@@ -0,0 +1,5 @@
+-- Нечеткий поиск названий с опечатками
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_media_name_ru_trgm ON media USING GIN (name_ru gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_media_name_en_trgm ON media USING GIN (name_en gin_trgm_ops);
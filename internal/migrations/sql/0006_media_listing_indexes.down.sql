@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_media_updated_at_id;
+DROP INDEX IF EXISTS idx_media_created_at_id;
+
+ALTER TABLE media ALTER COLUMN updated_at DROP NOT NULL;
+ALTER TABLE media ALTER COLUMN created_at DROP NOT NULL;
@@ -0,0 +1,9 @@
+-- Даты нужны для постраничного просмотра, пустые значения заполняем
+UPDATE media SET created_at = CURRENT_TIMESTAMP WHERE created_at IS NULL;
+UPDATE media SET updated_at = created_at WHERE updated_at IS NULL;
+ALTER TABLE media ALTER COLUMN created_at SET NOT NULL;
+ALTER TABLE media ALTER COLUMN updated_at SET NOT NULL;
+
+-- Индексы для постраничного просмотра по дате создания и обновления
+CREATE INDEX IF NOT EXISTS idx_media_created_at_id ON media (created_at, id);
+CREATE INDEX IF NOT EXISTS idx_media_updated_at_id ON media (updated_at, id);
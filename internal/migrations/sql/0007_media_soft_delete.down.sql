@@ -0,0 +1,5 @@
+DELETE FROM media WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_media_deleted_at;
+
+ALTER TABLE media DROP COLUMN IF EXISTS deleted_at;
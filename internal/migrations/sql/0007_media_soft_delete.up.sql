@@ -0,0 +1,4 @@
+-- Мягкое удаление: строки с deleted_at скрыты от чтения и удаляются задачей очистки
+ALTER TABLE media ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_media_deleted_at ON media (deleted_at);
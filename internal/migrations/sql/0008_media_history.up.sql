@@ -0,0 +1,12 @@
+-- История изменений медиа. Без внешнего ключа, чтобы история переживала окончательное удаление
+CREATE TABLE IF NOT EXISTS media_history (
+    id         BIGSERIAL PRIMARY KEY,
+    media_id   BIGINT       NOT NULL,
+    action     VARCHAR(20)  NOT NULL,
+    changes    JSONB        NOT NULL DEFAULT '{}'::jsonb,
+    actor      VARCHAR(255) NOT NULL,
+    request_id VARCHAR(64)  NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_media_history_media_id_id ON media_history (media_id, id);
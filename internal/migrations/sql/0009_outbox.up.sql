@@ -0,0 +1,18 @@
+-- Transactional outbox: события пишутся в одной транзакции с изменением медиа
+-- и публикуются в Kafka отдельным процессом
+CREATE TABLE IF NOT EXISTS outbox (
+    id              BIGSERIAL PRIMARY KEY,
+    aggregate_id    BIGINT       NOT NULL,
+    event_type      VARCHAR(100) NOT NULL,
+    message_key     VARCHAR(255) NOT NULL,
+    payload         JSONB        NOT NULL,
+    attempts        INT          NOT NULL DEFAULT 0,
+    last_error      TEXT         NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMPTZ  NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at      TIMESTAMPTZ  NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    sent_at         TIMESTAMPTZ
+);
+
+-- Поиск готовых к отправке строк и проверка более ранних неотправленных событий того же медиа
+CREATE INDEX IF NOT EXISTS idx_outbox_pending ON outbox (next_attempt_at, id) WHERE sent_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_pending_aggregate ON outbox (aggregate_id, id) WHERE sent_at IS NULL;
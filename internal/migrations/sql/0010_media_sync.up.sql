@@ -0,0 +1,8 @@
+-- Результат последней сверки медиа с Кинопоиском фоновой задачей.
+-- Записи, которые не удалось обновить, не выбираются повторно раньше остальных
+CREATE TABLE IF NOT EXISTS media_sync (
+    media_id   BIGINT       PRIMARY KEY REFERENCES media (id) ON DELETE CASCADE,
+    status     VARCHAR(20)  NOT NULL,
+    error      TEXT         NOT NULL DEFAULT '',
+    checked_at TIMESTAMPTZ  NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,2 @@
+-- Исходные значения типов не сохраняются, откатывать нечего
+SELECT 1;
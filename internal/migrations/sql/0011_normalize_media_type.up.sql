@@ -0,0 +1,6 @@
+-- Старые записи хранят тип как movie/tv, приводим их к значениям Кинопоиска,
+-- иначе такие записи не проходят проверку при обновлении.
+-- Это исправление данных, а не правка медиа: version не меняется, история и outbox не пишутся
+UPDATE media
+SET type = CASE lower(type) WHEN 'movie' THEN 'FILM' ELSE 'TV_SERIES' END
+WHERE lower(type) IN ('movie', 'tv');
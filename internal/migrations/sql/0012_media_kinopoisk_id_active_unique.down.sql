@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_media_kinopoisk_id_active;
+
+-- Не выполнится, если удаленная и неудаленная записи делят kinopoisk_id:
+-- лишние записи нужно сначала окончательно удалить через PurgeMedia
+ALTER TABLE media ADD CONSTRAINT media_kinopoisk_id_key UNIQUE (kinopoisk_id);
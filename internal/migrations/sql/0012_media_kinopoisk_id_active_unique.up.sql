@@ -0,0 +1,31 @@
+-- Мягко удаленное медиа не должно мешать повторному импорту того же фильма,
+-- поэтому kinopoisk_id уникален только среди неудаленных записей
+DO $$
+DECLARE
+    item record;
+BEGIN
+    -- Имя ограничения зависит от того, кто создал таблицу: миграция 0001 или GORM
+    FOR item IN
+        SELECT con.conname
+        FROM pg_constraint con
+        JOIN pg_attribute att ON att.attrelid = con.conrelid AND att.attnum = con.conkey[1]
+        WHERE con.conrelid = 'media'::regclass AND con.contype = 'u'
+          AND array_length(con.conkey, 1) = 1 AND att.attname = 'kinopoisk_id'
+    LOOP
+        EXECUTE format('ALTER TABLE media DROP CONSTRAINT %I', item.conname);
+    END LOOP;
+
+    -- Уникальный индекс без ограничения мог быть создан вручную
+    FOR item IN
+        SELECT cls.relname
+        FROM pg_index idx
+        JOIN pg_class cls ON cls.oid = idx.indexrelid
+        JOIN pg_attribute att ON att.attrelid = idx.indrelid AND att.attnum = idx.indkey[0]
+        WHERE idx.indrelid = 'media'::regclass AND idx.indisunique AND NOT idx.indisprimary
+          AND idx.indnatts = 1 AND idx.indpred IS NULL AND att.attname = 'kinopoisk_id'
+    LOOP
+        EXECUTE format('DROP INDEX %I', item.relname);
+    END LOOP;
+END $$;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_media_kinopoisk_id_active ON media (kinopoisk_id) WHERE deleted_at IS NULL;
@@ -0,0 +1,3 @@
+-- Поиск отправленных сообщений для удаления по сроку хранения.
+-- Неотправленные строки покрыты частичными индексами idx_outbox_pending*
+CREATE INDEX IF NOT EXISTS idx_outbox_sent_at ON outbox (sent_at) WHERE sent_at IS NOT NULL;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_media_year_id;
+DROP INDEX IF EXISTS idx_media_name_id;
@@ -0,0 +1,5 @@
+-- Индексы для постраничного просмотра по названию и году.
+-- Выражения совпадают с сортировкой ListMedia (sortExpressions в internal/repository/list.go),
+-- иначе планировщик не использует индекс
+CREATE INDEX IF NOT EXISTS idx_media_name_id ON media ((coalesce(nullif(name_ru, ''), name_en, '')), id);
+CREATE INDEX IF NOT EXISTS idx_media_year_id ON media ((coalesce(year, '')), id);
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/watchlist-kata/protos/media"
+)
+
+// ExportFilter ограничивает выгрузку по дате обновления
+type ExportFilter struct {
+	UpdatedSince   time.Time // Только медиа с updated_at не раньше, пусто - без ограничения
+	UpdatedBefore  time.Time // Только медиа с updated_at раньше, пусто - без ограничения
+	IncludeDeleted bool      // Выгружать и мягко удаленные медиа, с UpdatedSince - удаленные не раньше него
+}
+
+// ExportMedia возвращает до limit медиа с ID больше afterID в порядке ID.
+// Удаленные медиа выгружаются только с filter.IncludeDeleted и заполненным DeletedAt
+func (r *PostgresRepository) ExportMedia(ctx context.Context, afterID int64, filter ExportFilter, limit int) ([]*media.Media, error) {
+	if err := r.checkContextCancelled(ctx, "ExportMedia", map[string]interface{}{"after_id": afterID, "limit": limit}); err != nil {
+		return nil, err
+	}
+
+	query := r.db.WithContext(ctx).Model(&GormMedia{}).Where("media.id > ?", afterID)
+	switch {
+	case filter.IncludeDeleted && !filter.UpdatedSince.IsZero():
+		query = query.Unscoped().Where("(media.updated_at >= ? OR media.deleted_at >= ?)", filter.UpdatedSince, filter.UpdatedSince)
+	case filter.IncludeDeleted:
+		query = query.Unscoped()
+	case !filter.UpdatedSince.IsZero():
+		query = query.Where("media.updated_at >= ?", filter.UpdatedSince)
+	}
+	if !filter.UpdatedBefore.IsZero() {
+		query = query.Where("media.updated_at < ?", filter.UpdatedBefore)
+	}
+
+	var gormMedias []GormMedia
+	if err := query.Order("media.id").Limit(limit).Find(&gormMedias).Error; err != nil {
+		r.logger.ErrorContext(ctx, "Failed to export media", "after_id", afterID, "error", err)
+		return nil, fmt.Errorf("failed to export media after id %d: %w", afterID, err)
+	}
+
+	medias := make([]*media.Media, 0, len(gormMedias))
+	for i := range gormMedias {
+		medias = append(medias, convertGormMediaToProtoMedia(&gormMedias[i]))
+	}
+	if err := loadReferences(r.db.WithContext(ctx), medias...); err != nil {
+		r.logger.ErrorContext(ctx, "Failed to load media references", "error", err)
+		return nil, err
+	}
+	return medias, nil
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// TouchMedia отмечает, что медиа сверено с Кинопоиском и не изменилось: обновляет только updated_at.
+// Версия не меняется и запись в историю не добавляется. Если версия уже другая, запись не трогается
+func (r *PostgresRepository) TouchMedia(ctx context.Context, id int64, version int64) (time.Time, error) {
+	if err := r.checkContextCancelled(ctx, "TouchMedia", map[string]interface{}{"id": id, "version": version}); err != nil {
+		return time.Time{}, err
+	}
+
+	now := time.Now()
+	result := r.db.WithContext(ctx).Model(&GormMedia{}).
+		Where("id = ? AND version = ?", id, version).
+		UpdateColumn("updated_at", now)
+	if result.Error != nil {
+		r.logger.ErrorContext(ctx, "Failed to touch media", "id", id, "error", result.Error)
+		return time.Time{}, fmt.Errorf("failed to touch media with id %d: %w", id, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return time.Time{}, fmt.Errorf("%w: media with id %d is not at version %d", ErrVersionConflict, id, version)
+	}
+	return now, nil
+}
@@ -2,24 +2,28 @@ package repository
 
 import (
 	"fmt"
-	"github.com/watchlist-kata/protos/media"
 	"time"
+
+	"github.com/watchlist-kata/protos/media"
+	"gorm.io/gorm"
 )
 
 // GormMedia представляет структуру данных для работы с GORM и базой данных
 type GormMedia struct {
-	ID          int64     `gorm:"primaryKey"`                // primary key
-	KinopoiskID int64     `gorm:"unique"`                    // уникальный kinopoisk_id
-	Type        string    `gorm:"type:varchar(20)"`          // Тип (movie или tv)
-	NameEn      string    `gorm:"type:varchar(255)"`         // Название на английском
-	NameRu      string    `gorm:"type:varchar(255)"`         // Название на русском
-	Description string    `gorm:"type:text"`                 // Описание
-	Year        string    `gorm:"type:varchar(4)"`           // Год выпуска
-	Poster      string    `gorm:"type:varchar(255)"`         // URL постера
-	Countries   string    `gorm:"type:varchar(255)"`         // Страны
-	Genres      string    `gorm:"type:varchar(255)"`         // Жанры
-	CreatedAt   time.Time `gorm:"default:CURRENT_TIMESTAMP"` // Дата создания
-	UpdatedAt   time.Time `gorm:"default:CURRENT_TIMESTAMP"` // Дата обновления
+	ID          int64          `gorm:"primaryKey"`                // primary key
+	KinopoiskID int64          `gorm:"not null"`                  // kinopoisk_id, уникален среди неудаленных медиа
+	Type        string         `gorm:"type:varchar(20)"`          // Тип из Кинопоиска, например FILM или TV_SERIES
+	NameEn      string         `gorm:"type:varchar(255)"`         // Название на английском
+	NameRu      string         `gorm:"type:varchar(255)"`         // Название на русском
+	Description string         `gorm:"type:text"`                 // Описание
+	Year        string         `gorm:"type:varchar(9)"`           // Год выпуска или диапазон лет сериала
+	Poster      string         `gorm:"type:varchar(255)"`         // URL постера
+	Countries   string         `gorm:"type:text"`                 // Страны через запятую (устаревшее, см. media_countries)
+	Genres      string         `gorm:"type:text"`                 // Жанры через запятую (устаревшее, см. media_genres)
+	CreatedAt   time.Time      `gorm:"default:CURRENT_TIMESTAMP"` // Дата создания
+	UpdatedAt   time.Time      `gorm:"default:CURRENT_TIMESTAMP"` // Дата обновления
+	Version     int64          `gorm:"not null;default:1"`        // Версия для оптимистичной блокировки
+	DeletedAt   gorm.DeletedAt `gorm:"index"`                     // Дата мягкого удаления, скрывает запись от чтения
 }
 
 // TableName возвращает имя таблицы для GORM
@@ -29,7 +33,7 @@ func (GormMedia) TableName() string {
 
 // Улучшенный convertGormMediaToProtoMedia
 func convertGormMediaToProtoMedia(gormMedia *GormMedia) *media.Media {
-	return &media.Media{
+	m := &media.Media{
 		Id:          gormMedia.ID,
 		KinopoiskId: gormMedia.KinopoiskID,
 		Type:        gormMedia.Type,
@@ -42,7 +46,12 @@ func convertGormMediaToProtoMedia(gormMedia *GormMedia) *media.Media {
 		Genres:      gormMedia.Genres,
 		CreatedAt:   gormMedia.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:   gormMedia.UpdatedAt.Format(time.RFC3339),
+		Version:     gormMedia.Version,
+	}
+	if gormMedia.DeletedAt.Valid {
+		m.DeletedAt = gormMedia.DeletedAt.Time.Format(time.RFC3339)
 	}
+	return m
 }
 
 // Улучшенный convertProtoMediaToGormMedia
@@ -72,5 +81,6 @@ func convertProtoMediaToGormMedia(protoMedia *media.Media) GormMedia {
 		Genres:      protoMedia.Genres,
 		CreatedAt:   createdAt,
 		UpdatedAt:   updatedAt,
+		Version:     protoMedia.Version,
 	}
 }
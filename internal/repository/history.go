@@ -0,0 +1,236 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/watchlist-kata/media/internal/audit"
+	"github.com/watchlist-kata/protos/media"
+	"gorm.io/gorm"
+)
+
+// HistoryAction - тип изменения в истории медиа
+type HistoryAction string
+
+const (
+	HistoryActionCreate  HistoryAction = "create"
+	HistoryActionUpdate  HistoryAction = "update"
+	HistoryActionDelete  HistoryAction = "delete"
+	HistoryActionRestore HistoryAction = "restore"
+	HistoryActionPurge   HistoryAction = "purge"
+)
+
+// GormMediaHistory - строка таблицы media_history
+type GormMediaHistory struct {
+	ID        int64     `gorm:"primaryKey"`
+	MediaID   int64     `gorm:"not null"`
+	Action    string    `gorm:"type:varchar(20);not null"`
+	Changes   string    `gorm:"type:jsonb;not null"` // {"поле": {"before": ..., "after": ...}}
+	Actor     string    `gorm:"type:varchar(255);not null"`
+	RequestID string    `gorm:"type:varchar(64);not null"`
+	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+}
+
+// TableName возвращает имя таблицы для GORM
+func (GormMediaHistory) TableName() string {
+	return "media_history"
+}
+
+// fieldChange - значение поля до и после изменения
+type fieldChange struct {
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// auditedFields возвращает значения полей медиа, которые попадают в историю
+func auditedFields(m *GormMedia) map[string]string {
+	if m == nil {
+		return map[string]string{}
+	}
+	deletedAt := ""
+	if m.DeletedAt.Valid {
+		deletedAt = m.DeletedAt.Time.UTC().Format(time.RFC3339)
+	}
+	return map[string]string{
+		"kinopoisk_id": strconv.FormatInt(m.KinopoiskID, 10),
+		"type":         m.Type,
+		"name_en":      m.NameEn,
+		"name_ru":      m.NameRu,
+		"description":  m.Description,
+		"year":         m.Year,
+		"poster":       m.Poster,
+		"countries":    m.Countries,
+		"genres":       m.Genres,
+		"deleted_at":   deletedAt,
+	}
+}
+
+// diffMedia возвращает изменившиеся поля. before или after могут быть nil при создании и удалении
+func diffMedia(before, after *GormMedia) map[string]fieldChange {
+	beforeFields, afterFields := auditedFields(before), auditedFields(after)
+	changes := make(map[string]fieldChange)
+	for _, fields := range []map[string]string{beforeFields, afterFields} {
+		for field := range fields {
+			if beforeFields[field] != afterFields[field] {
+				changes[field] = fieldChange{Before: beforeFields[field], After: afterFields[field]}
+			}
+		}
+	}
+	return changes
+}
+
+// writeHistory добавляет запись в историю в рамках транзакции tx.
+// Автор и ID запроса берутся из контекста
+func writeHistory(ctx context.Context, tx *gorm.DB, mediaID int64, action HistoryAction, before, after *GormMedia) error {
+	changes, err := json.Marshal(diffMedia(before, after))
+	if err != nil {
+		return fmt.Errorf("failed to encode media changes: %w", err)
+	}
+	entry := GormMediaHistory{
+		MediaID:   mediaID,
+		Action:    string(action),
+		Changes:   string(changes),
+		Actor:     audit.ActorFrom(ctx),
+		RequestID: audit.RequestIDFrom(ctx),
+		CreatedAt: time.Now(),
+	}
+	if err := tx.Create(&entry).Error; err != nil {
+		return fmt.Errorf("failed to write media history: %w", err)
+	}
+	return nil
+}
+
+// encodeHistoryCursor и decodeHistoryCursor кодируют ID последней записи страницы
+func encodeHistoryCursor(id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(id, 10)))
+}
+
+func decodeHistoryCursor(value string) (int64, error) {
+	payload, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+	id, err := strconv.ParseInt(string(payload), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, ErrInvalidCursor
+	}
+	return id, nil
+}
+
+// GetMediaHistory возвращает страницу истории медиа, начиная с новых записей
+func (r *PostgresRepository) GetMediaHistory(ctx context.Context, mediaID int64, limit int, cursor string) ([]*media.MediaHistoryEntry, string, error) {
+	if err := r.checkContextCancelled(ctx, "GetMediaHistory", map[string]interface{}{"media_id": mediaID, "limit": limit}); err != nil {
+		return nil, "", err
+	}
+
+	query := r.db.WithContext(ctx).Where("media_id = ?", mediaID)
+	if cursor != "" {
+		lastID, err := decodeHistoryCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		query = query.Where("id < ?", lastID)
+	}
+
+	var rows []GormMediaHistory
+	if err := query.Order("id DESC").Limit(limit + 1).Find(&rows).Error; err != nil {
+		r.logger.ErrorContext(ctx, "Failed to get media history", "media_id", mediaID, "error", err)
+		return nil, "", fmt.Errorf("failed to get history of media with id %d: %w", mediaID, err)
+	}
+
+	var nextCursor string
+	if len(rows) > limit {
+		rows = rows[:limit]
+		nextCursor = encodeHistoryCursor(rows[len(rows)-1].ID)
+	}
+
+	entries := make([]*media.MediaHistoryEntry, 0, len(rows))
+	for i := range rows {
+		entry, err := convertHistoryToProto(&rows[i])
+		if err != nil {
+			r.logger.ErrorContext(ctx, "Failed to decode media history", "media_id", mediaID, "history_id", rows[i].ID, "error", err)
+			return nil, "", err
+		}
+		entries = append(entries, entry)
+	}
+
+	r.logger.InfoContext(ctx, "Media history retrieved successfully", "media_id", mediaID, "count", len(entries), "has_next", nextCursor != "")
+	return entries, nextCursor, nil
+}
+
+// convertHistoryToProto преобразует строку истории в proto, поля отсортированы по имени
+func convertHistoryToProto(row *GormMediaHistory) (*media.MediaHistoryEntry, error) {
+	var changes map[string]fieldChange
+	if err := json.Unmarshal([]byte(row.Changes), &changes); err != nil {
+		return nil, fmt.Errorf("failed to decode changes of history entry %d: %w", row.ID, err)
+	}
+	fields := make([]string, 0, len(changes))
+	for field := range changes {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	entry := &media.MediaHistoryEntry{
+		Id:        row.ID,
+		MediaId:   row.MediaID,
+		Action:    row.Action,
+		Actor:     row.Actor,
+		RequestId: row.RequestID,
+		CreatedAt: row.CreatedAt.Format(time.RFC3339),
+	}
+	for _, field := range fields {
+		entry.Changes = append(entry.Changes, &media.FieldChange{
+			Field:  field,
+			Before: changes[field].Before,
+			After:  changes[field].After,
+		})
+	}
+	return entry, nil
+}
+
+// ListHistoryAfter возвращает до limit записей истории всех медиа с id больше afterID по возрастанию id
+func (r *PostgresRepository) ListHistoryAfter(ctx context.Context, afterID int64, limit int) ([]*media.MediaHistoryEntry, error) {
+	if err := r.checkContextCancelled(ctx, "ListHistoryAfter", map[string]interface{}{"after_id": afterID, "limit": limit}); err != nil {
+		return nil, err
+	}
+
+	var rows []GormMediaHistory
+	if err := r.db.WithContext(ctx).Where("id > ?", afterID).Order("id").Limit(limit).Find(&rows).Error; err != nil {
+		r.logger.ErrorContext(ctx, "Failed to list media history", "after_id", afterID, "error", err)
+		return nil, fmt.Errorf("failed to list media history after %d: %w", afterID, err)
+	}
+
+	entries := make([]*media.MediaHistoryEntry, 0, len(rows))
+	for i := range rows {
+		entry, err := convertHistoryToProto(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// GetHistorySequence возвращает id последней записи истории, созданной раньше before,
+// или последней записи вообще, если before не задан. 0 - записей нет
+func (r *PostgresRepository) GetHistorySequence(ctx context.Context, before time.Time) (int64, error) {
+	if err := r.checkContextCancelled(ctx, "GetHistorySequence", map[string]interface{}{"before": before}); err != nil {
+		return 0, err
+	}
+
+	query := r.db.WithContext(ctx).Model(&GormMediaHistory{})
+	if !before.IsZero() {
+		query = query.Where("created_at < ?", before)
+	}
+	var sequence int64
+	if err := query.Select("coalesce(max(id), 0)").Scan(&sequence).Error; err != nil {
+		r.logger.ErrorContext(ctx, "Failed to get media history sequence", "before", before, "error", err)
+		return 0, fmt.Errorf("failed to get media history sequence: %w", err)
+	}
+	return sequence, nil
+}
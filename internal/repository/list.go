@@ -0,0 +1,229 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/watchlist-kata/protos/media"
+)
+
+// ErrInvalidCursor - курсор поврежден или не соответствует параметрам запроса
+var ErrInvalidCursor = errors.New("invalid page cursor")
+
+// SortField определяет поле сортировки для ListMedia
+type SortField string
+
+const (
+	SortByID        SortField = "id"
+	SortByName      SortField = "name"
+	SortByYear      SortField = "year"
+	SortByCreatedAt SortField = "created_at"
+	SortByUpdatedAt SortField = "updated_at"
+)
+
+// sortExpressions - SQL выражения для сортировки, значения не бывают NULL.
+// Для каждого выражения есть индекс (выражение, id), при изменении нужна миграция
+var sortExpressions = map[SortField]string{
+	SortByID:        "media.id",
+	SortByName:      "coalesce(nullif(media.name_ru, ''), media.name_en, '')",
+	SortByYear:      "coalesce(media.year, '')",
+	SortByCreatedAt: "media.created_at",
+	SortByUpdatedAt: "media.updated_at",
+}
+
+// yearStartExpr - год выпуска (начало диапазона для сериалов) как число, NULL если год не задан
+const yearStartExpr = "(CASE WHEN media.year ~ '^[0-9]{4}' THEN left(media.year, 4)::int END)"
+
+// ListMediaParams содержит параметры постраничного просмотра медиа
+type ListMediaParams struct {
+	Limit      int       // Размер страницы
+	Cursor     string    // Курсор из предыдущей страницы, пусто - первая страница
+	SortBy     SortField // Поле сортировки
+	Descending bool      // Сортировка по убыванию
+	Type       string    // Фильтр по типу
+	YearFrom   int       // Фильтр по году выпуска (включительно), 0 - без ограничения
+	YearTo     int       // Фильтр по году выпуска (включительно), 0 - без ограничения
+	Genre      string    // Фильтр по жанру
+	Country    string    // Фильтр по стране
+}
+
+// listCursor - содержимое непрозрачного курсора: позиция последней записи страницы
+// и отпечаток параметров запроса, для которого он выдан
+type listCursor struct {
+	SortBy     SortField `json:"s"`
+	Descending bool      `json:"d"`
+	Filters    string    `json:"f"`
+	Value      string    `json:"v"`
+	ID         int64     `json:"id"`
+}
+
+// paramsFingerprint возвращает хеш сортировки и фильтров запроса. Курсор с другим
+// отпечатком указывает на позицию в другой выборке и пропустил бы или повторил записи
+func paramsFingerprint(params ListMediaParams) string {
+	h := fnv.New64a()
+	h.Write([]byte(strings.Join([]string{
+		string(params.SortBy),
+		strconv.FormatBool(params.Descending),
+		params.Type,
+		strconv.Itoa(params.YearFrom),
+		strconv.Itoa(params.YearTo),
+		strings.ToLower(params.Genre),
+		strings.ToLower(params.Country),
+	}, "\x00")))
+	return strconv.FormatUint(h.Sum64(), 36)
+}
+
+// encodeCursor кодирует курсор в base64
+func encodeCursor(c listCursor) string {
+	payload, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(payload)
+}
+
+// decodeCursor декодирует курсор и проверяет, что он выдан для той же сортировки и тех же фильтров
+func decodeCursor(value string, params ListMediaParams) (listCursor, error) {
+	var c listCursor
+	payload, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+	if err := json.Unmarshal(payload, &c); err != nil {
+		return c, ErrInvalidCursor
+	}
+	if c.SortBy != params.SortBy || c.Descending != params.Descending {
+		return c, fmt.Errorf("%w: cursor was issued for a different sort order", ErrInvalidCursor)
+	}
+	if c.Filters != paramsFingerprint(params) {
+		return c, fmt.Errorf("%w: cursor was issued for different filters", ErrInvalidCursor)
+	}
+	return c, nil
+}
+
+// sortValue возвращает значение поля сортировки записи для курсора
+func sortValue(m *GormMedia, sortBy SortField) string {
+	switch sortBy {
+	case SortByName:
+		if m.NameRu != "" {
+			return m.NameRu
+		}
+		return m.NameEn
+	case SortByYear:
+		return m.Year
+	case SortByCreatedAt:
+		return m.CreatedAt.Format(time.RFC3339Nano)
+	case SortByUpdatedAt:
+		return m.UpdatedAt.Format(time.RFC3339Nano)
+	default:
+		return ""
+	}
+}
+
+// cursorValue преобразует значение из курсора в параметр запроса
+func cursorValue(c listCursor) (interface{}, error) {
+	switch c.SortBy {
+	case SortByCreatedAt, SortByUpdatedAt:
+		t, err := time.Parse(time.RFC3339Nano, c.Value)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		return t, nil
+	default:
+		return c.Value, nil
+	}
+}
+
+// ListMedia возвращает страницу медиа с keyset-пагинацией и курсор следующей страницы
+func (r *PostgresRepository) ListMedia(ctx context.Context, params ListMediaParams) ([]*media.Media, string, error) {
+	if err := r.checkContextCancelled(ctx, "ListMedia", map[string]interface{}{"sort_by": params.SortBy, "limit": params.Limit}); err != nil {
+		return nil, "", err
+	}
+
+	expr, ok := sortExpressions[params.SortBy]
+	if !ok {
+		return nil, "", fmt.Errorf("unknown sort field %q", params.SortBy)
+	}
+
+	query := r.db.WithContext(ctx).Model(&GormMedia{})
+
+	if params.Type != "" {
+		query = query.Where("media.type = ?", params.Type)
+	}
+	if params.YearFrom > 0 {
+		query = query.Where(yearStartExpr+" >= ?", params.YearFrom)
+	}
+	if params.YearTo > 0 {
+		query = query.Where(yearStartExpr+" <= ?", params.YearTo)
+	}
+	if params.Genre != "" {
+		query = query.Where(`EXISTS (SELECT 1 FROM media_genres mg JOIN genres g ON g.id = mg.genre_id
+			WHERE mg.media_id = media.id AND lower(g.name) = lower(?))`, params.Genre)
+	}
+	if params.Country != "" {
+		query = query.Where(`EXISTS (SELECT 1 FROM media_countries mc JOIN countries c ON c.id = mc.country_id
+			WHERE mc.media_id = media.id AND lower(c.name) = lower(?))`, params.Country)
+	}
+
+	comparison, direction := ">", "ASC"
+	if params.Descending {
+		comparison, direction = "<", "DESC"
+	}
+
+	if params.Cursor != "" {
+		cursor, err := decodeCursor(params.Cursor, params)
+		if err != nil {
+			return nil, "", err
+		}
+		if params.SortBy == SortByID {
+			query = query.Where(fmt.Sprintf("media.id %s ?", comparison), cursor.ID)
+		} else {
+			value, err := cursorValue(cursor)
+			if err != nil {
+				return nil, "", err
+			}
+			query = query.Where(fmt.Sprintf("(%s, media.id) %s (?, ?)", expr, comparison), value, cursor.ID)
+		}
+	}
+
+	if params.SortBy != SortByID {
+		query = query.Order(fmt.Sprintf("%s %s", expr, direction))
+	}
+	query = query.Order(fmt.Sprintf("media.id %s", direction))
+
+	// Запрашиваем на одну запись больше, чтобы понять, есть ли следующая страница
+	var gormMedias []GormMedia
+	if err := query.Limit(params.Limit + 1).Find(&gormMedias).Error; err != nil {
+		r.logger.ErrorContext(ctx, "Failed to list media", "sort_by", params.SortBy, "error", err)
+		return nil, "", fmt.Errorf("failed to list media: %w", err)
+	}
+
+	var nextCursor string
+	if len(gormMedias) > params.Limit {
+		gormMedias = gormMedias[:params.Limit]
+		last := &gormMedias[len(gormMedias)-1]
+		nextCursor = encodeCursor(listCursor{
+			SortBy:     params.SortBy,
+			Descending: params.Descending,
+			Filters:    paramsFingerprint(params),
+			Value:      sortValue(last, params.SortBy),
+			ID:         last.ID,
+		})
+	}
+
+	medias := make([]*media.Media, 0, len(gormMedias))
+	for i := range gormMedias {
+		medias = append(medias, convertGormMediaToProtoMedia(&gormMedias[i]))
+	}
+	if err := loadReferences(r.db.WithContext(ctx), medias...); err != nil {
+		r.logger.ErrorContext(ctx, "Failed to load media references", "error", err)
+		return nil, "", err
+	}
+
+	r.logger.InfoContext(ctx, "Media listed successfully", "sort_by", params.SortBy, "count", len(medias), "has_next", nextCursor != "")
+	return medias, nextCursor, nil
+}
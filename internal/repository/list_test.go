@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	params := ListMediaParams{SortBy: SortByName, Descending: true, Type: "FILM", YearFrom: 1990, Genre: "драма"}
+
+	tests := []struct {
+		name   string
+		cursor listCursor
+	}{
+		{name: "by id", cursor: listCursor{SortBy: SortByName, Descending: true, ID: 42}},
+		{name: "unicode value", cursor: listCursor{SortBy: SortByName, Descending: true, Value: "Ёлки 2", ID: 7}},
+		{name: "empty value", cursor: listCursor{SortBy: SortByName, Descending: true, Value: "", ID: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cursor.Filters = paramsFingerprint(params)
+			got, err := decodeCursor(encodeCursor(tt.cursor), params)
+			if err != nil {
+				t.Fatalf("decodeCursor failed: %v", err)
+			}
+			if got != tt.cursor {
+				t.Errorf("decoded %+v, want %+v", got, tt.cursor)
+			}
+		})
+	}
+}
+
+func TestDecodeCursorRejectsInvalid(t *testing.T) {
+	params := ListMediaParams{SortBy: SortByYear, Type: "FILM", Genre: "Драма"}
+	valid := listCursor{SortBy: SortByYear, Filters: paramsFingerprint(params), Value: "1999", ID: 3}
+
+	withParams := func(modify func(p *ListMediaParams)) ListMediaParams {
+		p := params
+		modify(&p)
+		return p
+	}
+
+	tests := []struct {
+		name   string
+		value  string
+		params ListMediaParams
+	}{
+		{name: "not base64", value: "%%%", params: params},
+		{name: "not json", value: base64.RawURLEncoding.EncodeToString([]byte("not json")), params: params},
+		{name: "padded base64", value: base64.URLEncoding.EncodeToString([]byte(`{"s":"year"}`)) + "=", params: params},
+		{name: "other sort field", value: encodeCursor(valid), params: withParams(func(p *ListMediaParams) { p.SortBy = SortByName })},
+		{name: "other direction", value: encodeCursor(valid), params: withParams(func(p *ListMediaParams) { p.Descending = true })},
+		{name: "other type", value: encodeCursor(valid), params: withParams(func(p *ListMediaParams) { p.Type = "TV_SERIES" })},
+		{name: "added year filter", value: encodeCursor(valid), params: withParams(func(p *ListMediaParams) { p.YearTo = 2000 })},
+		{name: "removed genre filter", value: encodeCursor(valid), params: withParams(func(p *ListMediaParams) { p.Genre = "" })},
+		{name: "added country filter", value: encodeCursor(valid), params: withParams(func(p *ListMediaParams) { p.Country = "США" })},
+		{name: "cursor without filters", value: encodeCursor(listCursor{SortBy: SortByYear, Value: "1999", ID: 3}), params: params},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decodeCursor(tt.value, tt.params); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("decodeCursor error = %v, want ErrInvalidCursor", err)
+			}
+		})
+	}
+}
+
+func TestParamsFingerprintIgnoresPageAndCase(t *testing.T) {
+	a := ListMediaParams{SortBy: SortByID, Genre: "Драма", Country: "США", Limit: 10}
+	b := ListMediaParams{SortBy: SortByID, Genre: "драма", Country: "сша", Limit: 50, Cursor: "abc"}
+	if paramsFingerprint(a) != paramsFingerprint(b) {
+		t.Error("fingerprint depends on page size, cursor or filter case")
+	}
+}
+
+func TestCursorValue(t *testing.T) {
+	at := time.Date(2024, 5, 1, 12, 30, 0, 123456789, time.UTC)
+
+	tests := []struct {
+		name    string
+		cursor  listCursor
+		want    interface{}
+		wantErr bool
+	}{
+		{name: "name", cursor: listCursor{SortBy: SortByName, Value: "Матрица"}, want: "Матрица"},
+		{name: "year", cursor: listCursor{SortBy: SortByYear, Value: "2010-2015"}, want: "2010-2015"},
+		{name: "created at", cursor: listCursor{SortBy: SortByCreatedAt, Value: at.Format(time.RFC3339Nano)}, want: at},
+		{name: "updated at", cursor: listCursor{SortBy: SortByUpdatedAt, Value: at.Format(time.RFC3339Nano)}, want: at},
+		{name: "broken time", cursor: listCursor{SortBy: SortByUpdatedAt, Value: "yesterday"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cursorValue(tt.cursor)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidCursor) {
+					t.Fatalf("cursorValue error = %v, want ErrInvalidCursor", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("cursorValue failed: %v", err)
+			}
+			if gotTime, ok := got.(time.Time); ok {
+				if !gotTime.Equal(tt.want.(time.Time)) {
+					t.Errorf("cursorValue = %v, want %v", got, tt.want)
+				}
+				return
+			}
+			if got != tt.want {
+				t.Errorf("cursorValue = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
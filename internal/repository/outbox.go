@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/watchlist-kata/media/internal/audit"
+	"github.com/watchlist-kata/media/internal/events"
+	"gorm.io/gorm"
+)
+
+// OutboxMessage - строка таблицы outbox, событие об изменении медиа
+type OutboxMessage struct {
+	ID            int64      `gorm:"primaryKey"`
+	AggregateID   int64      `gorm:"not null"`                   // ID медиа
+	EventType     string     `gorm:"type:varchar(100);not null"` // Тип доменного события, например MediaUpdated
+	MessageKey    string     `gorm:"type:varchar(255);not null"` // Ключ сообщения Kafka
+	Payload       string     `gorm:"type:jsonb;not null"`        // Тело сообщения
+	Attempts      int        `gorm:"not null;default:0"`         // Количество попыток отправки
+	LastError     string     `gorm:"type:text;not null"`         // Ошибка последней попытки
+	NextAttemptAt time.Time  `gorm:"default:CURRENT_TIMESTAMP"`  // Не отправлять раньше этого времени
+	CreatedAt     time.Time  `gorm:"default:CURRENT_TIMESTAMP"`
+	SentAt        *time.Time // Время успешной отправки, nil - не отправлено
+}
+
+// TableName возвращает имя таблицы для GORM
+func (OutboxMessage) TableName() string {
+	return "outbox"
+}
+
+// eventTypes сопоставляет действие из истории с типом доменного события
+var eventTypes = map[HistoryAction]events.Type{
+	HistoryActionCreate:  events.MediaCreated,
+	HistoryActionUpdate:  events.MediaUpdated,
+	HistoryActionDelete:  events.MediaDeleted,
+	HistoryActionRestore: events.MediaRestored,
+	HistoryActionPurge:   events.MediaDeleted,
+}
+
+// newMediaEvent создает доменное событие об изменении медиа
+func newMediaEvent(ctx context.Context, mediaID int64, action HistoryAction, before, after *GormMedia) *events.MediaEvent {
+	changes := make(map[string]events.FieldChange)
+	for field, change := range diffMedia(before, after) {
+		changes[field] = events.FieldChange{Before: change.Before, After: change.After}
+	}
+
+	var version int64
+	if after != nil {
+		version = after.Version
+	} else if before != nil {
+		version = before.Version
+	}
+
+	event := events.NewMediaEvent(eventTypes[action], mediaID, version, changes)
+	event.Actor = audit.ActorFrom(ctx)
+	event.RequestID = audit.RequestIDFrom(ctx)
+	event.Purged = action == HistoryActionPurge
+	if after != nil && !after.DeletedAt.Valid {
+		event.Media = &events.MediaSnapshot{
+			ID:          after.ID,
+			KinopoiskID: after.KinopoiskID,
+			Type:        after.Type,
+			NameEn:      after.NameEn,
+			NameRu:      after.NameRu,
+			Description: after.Description,
+			Year:        after.Year,
+			Poster:      after.Poster,
+			Countries:   after.Countries,
+			Genres:      after.Genres,
+			Version:     after.Version,
+			CreatedAt:   after.CreatedAt,
+			UpdatedAt:   after.UpdatedAt,
+		}
+	}
+	return event
+}
+
+// writeOutbox добавляет доменное событие об изменении медиа в outbox в рамках транзакции tx
+func writeOutbox(ctx context.Context, tx *gorm.DB, mediaID int64, action HistoryAction, before, after *GormMedia) error {
+	event := newMediaEvent(ctx, mediaID, action, before, after)
+	payload, err := event.Encode()
+	if err != nil {
+		return err
+	}
+	message := OutboxMessage{
+		AggregateID:   mediaID,
+		EventType:     string(event.Type),
+		MessageKey:    strconv.FormatInt(mediaID, 10),
+		Payload:       string(payload),
+		NextAttemptAt: time.Now(),
+		CreatedAt:     time.Now(),
+	}
+	if err := tx.Create(&message).Error; err != nil {
+		return fmt.Errorf("failed to write outbox message: %w", err)
+	}
+	return nil
+}
+
+// recordChange записывает изменение медиа в историю и outbox в рамках транзакции tx
+func recordChange(ctx context.Context, tx *gorm.DB, mediaID int64, action HistoryAction, before, after *GormMedia) error {
+	if err := writeHistory(ctx, tx, mediaID, action, before, after); err != nil {
+		return err
+	}
+	return writeOutbox(ctx, tx, mediaID, action, before, after)
+}
+
+// ClaimOutbox забирает до limit готовых к отправке сообщений и откладывает их на lease,
+// чтобы другие реплики их не взяли. Берется только самое раннее неотправленное
+// сообщение каждого медиа, поэтому события одного медиа публикуются по порядку
+func (r *PostgresRepository) ClaimOutbox(ctx context.Context, limit int, lease time.Duration) ([]OutboxMessage, error) {
+	if err := r.checkContextCancelled(ctx, "ClaimOutbox", map[string]interface{}{"limit": limit}); err != nil {
+		return nil, err
+	}
+
+	var messages []OutboxMessage
+	err := r.db.WithContext(ctx).Raw(`
+		UPDATE outbox SET attempts = attempts + 1, next_attempt_at = now() + make_interval(secs => ?)
+		WHERE id IN (
+			SELECT o.id FROM outbox o
+			WHERE o.sent_at IS NULL AND o.next_attempt_at <= now()
+				AND NOT EXISTS (
+					SELECT 1 FROM outbox earlier
+					WHERE earlier.aggregate_id = o.aggregate_id AND earlier.sent_at IS NULL AND earlier.id < o.id
+				)
+			ORDER BY o.id
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, lease.Seconds(), limit).Scan(&messages).Error
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Failed to claim outbox messages", "limit", limit, "error", err)
+		return nil, fmt.Errorf("failed to claim outbox messages: %w", err)
+	}
+
+	// RETURNING не гарантирует порядок
+	sort.Slice(messages, func(i, j int) bool { return messages[i].ID < messages[j].ID })
+	return messages, nil
+}
+
+// MarkOutboxSent отмечает сообщения как отправленные
+func (r *PostgresRepository) MarkOutboxSent(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	err := r.db.WithContext(ctx).Model(&OutboxMessage{}).
+		Where("id IN ?", ids).
+		Updates(map[string]interface{}{"sent_at": time.Now(), "last_error": ""}).Error
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Failed to mark outbox messages as sent", "count", len(ids), "error", err)
+		return fmt.Errorf("failed to mark outbox messages as sent: %w", err)
+	}
+	return nil
+}
+
+// MarkOutboxFailed сохраняет ошибку отправки и время следующей попытки
+func (r *PostgresRepository) MarkOutboxFailed(ctx context.Context, id int64, sendErr error, nextAttemptAt time.Time) error {
+	err := r.db.WithContext(ctx).Model(&OutboxMessage{}).
+		Where("id = ? AND sent_at IS NULL", id).
+		Updates(map[string]interface{}{"last_error": sendErr.Error(), "next_attempt_at": nextAttemptAt}).Error
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Failed to mark outbox message as failed", "id", id, "error", err)
+		return fmt.Errorf("failed to mark outbox message %d as failed: %w", id, err)
+	}
+	return nil
+}
+
+// DeleteSentOutbox удаляет до limit сообщений, отправленных раньше before
+func (r *PostgresRepository) DeleteSentOutbox(ctx context.Context, before time.Time, limit int) (int64, error) {
+	if err := r.checkContextCancelled(ctx, "DeleteSentOutbox", map[string]interface{}{"before": before, "limit": limit}); err != nil {
+		return 0, err
+	}
+
+	result := r.db.WithContext(ctx).Exec(`
+		DELETE FROM outbox WHERE id IN (
+			SELECT id FROM outbox WHERE sent_at IS NOT NULL AND sent_at < ? ORDER BY sent_at LIMIT ?
+		)`, before, limit)
+	if result.Error != nil {
+		r.logger.ErrorContext(ctx, "Failed to delete sent outbox messages", "before", before, "error", result.Error)
+		return 0, fmt.Errorf("failed to delete outbox messages sent before %s: %w", before.Format(time.RFC3339), result.Error)
+	}
+	return result.RowsAffected, nil
+}
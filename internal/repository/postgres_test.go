@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/watchlist-kata/media/internal/migrations"
+	"github.com/watchlist-kata/protos/media"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// testDSNEnv - строка подключения к тестовой базе. Без нее тесты с Postgres пропускаются
+const testDSNEnv = "MEDIA_TEST_DATABASE_DSN"
+
+// newTestRepository подключается к тестовой базе и применяет миграции
+func newTestRepository(t *testing.T) Repository {
+	t.Helper()
+	dsn := os.Getenv(testDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", testDSNEnv)
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{TranslateError: true})
+	if err != nil {
+		t.Fatalf("failed to connect to database: %v", err)
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	migrator, err := migrations.NewMigrator(db, logger)
+	if err != nil {
+		t.Fatalf("failed to load migrations: %v", err)
+	}
+	if err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("failed to apply migrations: %v", err)
+	}
+	return NewPostgresRepository(db, logger, 0)
+}
+
+func TestReimportAfterSoftDelete(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+	// Уникальный ID, чтобы повторные запуски не зависели от оставшихся данных
+	kinopoiskID := time.Now().UnixNano()
+
+	newMedia := func() *media.Media {
+		return &media.Media{KinopoiskId: kinopoiskID, Type: "FILM", NameRu: "Тестовый фильм", Year: "2001"}
+	}
+
+	first, err := repo.CreateMedia(ctx, newMedia())
+	if err != nil {
+		t.Fatalf("failed to create media: %v", err)
+	}
+	t.Cleanup(func() { _, _ = repo.PurgeMedia(context.Background(), first.Id) })
+
+	if _, err := repo.CreateMedia(ctx, newMedia()); !errors.Is(err, ErrDuplicateKinopoiskID) {
+		t.Fatalf("second create of a live media: error = %v, want ErrDuplicateKinopoiskID", err)
+	}
+
+	if _, err := repo.DeleteMedia(ctx, first.Id, first.Version); err != nil {
+		t.Fatalf("failed to delete media: %v", err)
+	}
+	if _, err := repo.GetMediaByKinopoiskID(ctx, kinopoiskID); !errors.Is(err, ErrMediaNotFound) {
+		t.Fatalf("lookup of deleted media: error = %v, want ErrMediaNotFound", err)
+	}
+
+	second, err := repo.CreateMedia(ctx, newMedia())
+	if err != nil {
+		t.Fatalf("re-import after soft delete failed: %v", err)
+	}
+	t.Cleanup(func() { _, _ = repo.PurgeMedia(context.Background(), second.Id) })
+	if second.Id == first.Id {
+		t.Fatalf("re-import reused id %d of the deleted media", first.Id)
+	}
+
+	found, err := repo.GetMediaByKinopoiskID(ctx, kinopoiskID)
+	if err != nil || found.Id != second.Id {
+		t.Fatalf("lookup after re-import = %v, %v, want media %d", found, err, second.Id)
+	}
+
+	// Восстановить удаленную копию нельзя, пока есть неудаленная
+	if _, err := repo.RestoreMedia(ctx, first.Id); !errors.Is(err, ErrDuplicateKinopoiskID) {
+		t.Fatalf("restore over a live media: error = %v, want ErrDuplicateKinopoiskID", err)
+	}
+}
+
+func TestExportIncludeDeleted(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+	kinopoiskID := time.Now().UnixNano()
+	since := time.Now().Add(-time.Minute)
+
+	created, err := repo.CreateMedia(ctx, &media.Media{KinopoiskId: kinopoiskID, Type: "FILM", NameRu: "Удаленный фильм"})
+	if err != nil {
+		t.Fatalf("failed to create media: %v", err)
+	}
+	t.Cleanup(func() { _, _ = repo.PurgeMedia(context.Background(), created.Id) })
+	if _, err := repo.DeleteMedia(ctx, created.Id, created.Version); err != nil {
+		t.Fatalf("failed to delete media: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		filter      ExportFilter
+		wantFound   bool
+		wantDeleted bool
+	}{
+		{name: "deleted media are skipped by default", filter: ExportFilter{UpdatedSince: since}},
+		{name: "include deleted", filter: ExportFilter{IncludeDeleted: true}, wantFound: true, wantDeleted: true},
+		{name: "include deleted since", filter: ExportFilter{UpdatedSince: since, IncludeDeleted: true}, wantFound: true, wantDeleted: true},
+		{name: "deleted before since", filter: ExportFilter{UpdatedSince: time.Now().Add(time.Hour), IncludeDeleted: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			medias, err := repo.ExportMedia(ctx, created.Id-1, tt.filter, 1)
+			if err != nil {
+				t.Fatalf("ExportMedia failed: %v", err)
+			}
+			var found *media.Media
+			if len(medias) == 1 && medias[0].Id == created.Id {
+				found = medias[0]
+			}
+			if (found != nil) != tt.wantFound {
+				t.Fatalf("exported %v, want media %d exported: %v", medias, created.Id, tt.wantFound)
+			}
+			if found != nil && (found.DeletedAt != "") != tt.wantDeleted {
+				t.Errorf("deleted_at = %q, want set: %v", found.DeletedAt, tt.wantDeleted)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/watchlist-kata/protos/media"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// referenceTable описывает справочник (жанры или страны) и его таблицу связей с media
+type referenceTable struct {
+	table      string // Справочник, например genres
+	linkTable  string // Таблица связей, например media_genres
+	linkColumn string // Колонка справочника в таблице связей, например genre_id
+}
+
+var (
+	genresTable    = referenceTable{table: "genres", linkTable: "media_genres", linkColumn: "genre_id"}
+	countriesTable = referenceTable{table: "countries", linkTable: "media_countries", linkColumn: "country_id"}
+)
+
+// referenceRow - строка справочника
+type referenceRow struct {
+	ID   int64
+	Name string
+}
+
+// referenceLink - название из справочника, связанное с медиа
+type referenceLink struct {
+	MediaID int64
+	Name    string
+}
+
+// splitNames разбирает устаревшую строку "Драма, Комедия" в список
+func splitNames(value string) []string {
+	var names []string
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// normalizeNames приводит список и устаревшую строку к согласованному виду.
+// Список имеет приоритет, строка используется только если список пуст
+func normalizeNames(list []string, legacy string) ([]string, string) {
+	if len(list) == 0 {
+		list = splitNames(legacy)
+	}
+
+	seen := make(map[string]struct{}, len(list))
+	names := make([]string, 0, len(list))
+	for _, name := range list {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		names = append(names, name)
+	}
+	return names, strings.Join(names, ", ")
+}
+
+// NormalizeReferences согласует жанры и страны медиа так же, как они сохраняются при записи
+func NormalizeReferences(m *media.Media) {
+	m.GenreList, m.Genres = normalizeNames(m.GenreList, m.Genres)
+	m.CountryList, m.Countries = normalizeNames(m.CountryList, m.Countries)
+}
+
+// replaceLinks заменяет связи медиа со справочником на names, создавая недостающие записи
+func replaceLinks(tx *gorm.DB, ref referenceTable, mediaID int64, names []string) error {
+	if err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE media_id = ?", ref.linkTable), mediaID).Error; err != nil {
+		return fmt.Errorf("failed to clear %s for media %d: %w", ref.linkTable, mediaID, err)
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	rows := make([]referenceRow, len(names))
+	for i, name := range names {
+		rows[i] = referenceRow{Name: name}
+	}
+	// DO UPDATE нужен, чтобы RETURNING вернул id и для уже существующих записей
+	err := tx.Table(ref.table).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name"}),
+	}).Create(&rows).Error
+	if err != nil {
+		return fmt.Errorf("failed to upsert %s: %w", ref.table, err)
+	}
+
+	links := make([]map[string]interface{}, len(rows))
+	for i, row := range rows {
+		links[i] = map[string]interface{}{"media_id": mediaID, ref.linkColumn: row.ID, "position": i}
+	}
+	if err := tx.Table(ref.linkTable).Create(links).Error; err != nil {
+		return fmt.Errorf("failed to link %s for media %d: %w", ref.table, mediaID, err)
+	}
+	return nil
+}
+
+// saveReferences сохраняет жанры и страны медиа в справочники
+func saveReferences(tx *gorm.DB, m *media.Media) error {
+	if err := replaceLinks(tx, genresTable, m.Id, m.GenreList); err != nil {
+		return err
+	}
+	return replaceLinks(tx, countriesTable, m.Id, m.CountryList)
+}
+
+// loadLinks возвращает названия из справочника для медиа, сгруппированные по media_id в порядке position
+func loadLinks(db *gorm.DB, ref referenceTable, mediaIDs []int64) (map[int64][]string, error) {
+	var links []referenceLink
+	err := db.Table(ref.linkTable+" AS l").
+		Select("l.media_id, r.name").
+		Joins(fmt.Sprintf("JOIN %s r ON r.id = l.%s", ref.table, ref.linkColumn)).
+		Where("l.media_id IN ?", mediaIDs).
+		Order("l.media_id, l.position").
+		Scan(&links).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", ref.table, err)
+	}
+
+	result := make(map[int64][]string, len(mediaIDs))
+	for _, link := range links {
+		result[link.MediaID] = append(result[link.MediaID], link.Name)
+	}
+	return result, nil
+}
+
+// loadReferences заполняет GenreList и CountryList одним запросом на справочник.
+// Для записей без связей список берется из устаревшей строки
+func loadReferences(db *gorm.DB, medias ...*media.Media) error {
+	if len(medias) == 0 {
+		return nil
+	}
+	ids := make([]int64, len(medias))
+	for i, m := range medias {
+		ids[i] = m.Id
+	}
+
+	genres, err := loadLinks(db, genresTable, ids)
+	if err != nil {
+		return err
+	}
+	countries, err := loadLinks(db, countriesTable, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range medias {
+		m.GenreList, m.CountryList = genres[m.Id], countries[m.Id]
+		if len(m.GenreList) == 0 {
+			m.GenreList = splitNames(m.Genres)
+		}
+		if len(m.CountryList) == 0 {
+			m.CountryList = splitNames(m.Countries)
+		}
+	}
+	return nil
+}
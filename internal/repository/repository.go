@@ -5,41 +5,71 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
-	"strings"
+	"time"
 
 	"github.com/watchlist-kata/protos/media"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-// ErrMediaNotFound - ошибка, возникающая когда медиа не найдено
-var ErrMediaNotFound = errors.New("media not found")
+var (
+	// ErrMediaNotFound - ошибка, возникающая когда медиа не найдено
+	ErrMediaNotFound = errors.New("media not found")
+	// ErrDuplicateKinopoiskID - медиа с таким kinopoisk_id уже существует
+	ErrDuplicateKinopoiskID = errors.New("media with this kinopoisk_id already exists")
+	// ErrKinopoiskIDMismatch - попытка изменить kinopoisk_id существующего медиа
+	ErrKinopoiskIDMismatch = errors.New("kinopoisk_id mismatch")
+	// ErrVersionConflict - запись была изменена после чтения клиентом
+	ErrVersionConflict = errors.New("media version conflict")
+)
 
 // Repository определяет интерфейс для репозитория
 type Repository interface {
 	GetMediaByID(ctx context.Context, id int64) (*media.Media, error)
+	GetMediaHistory(ctx context.Context, mediaID int64, limit int, cursor string) ([]*media.MediaHistoryEntry, string, error)
+	ListHistoryAfter(ctx context.Context, afterID int64, limit int) ([]*media.MediaHistoryEntry, error)
+	GetHistorySequence(ctx context.Context, before time.Time) (int64, error)
 	GetMediaByKinopoiskID(ctx context.Context, kinopoiskID int64) (*media.Media, error)
-	GetMediasByNameFromRepo(ctx context.Context, name string) ([]*media.Media, error)
+	GetMediasByNameFromRepo(ctx context.Context, name string, mode SearchMode) ([]*media.Media, error)
 	CreateMedia(ctx context.Context, media *media.Media) (*media.Media, error)
-	UpdateMedia(ctx context.Context, media *media.Media) (*media.Media, error)
-	DeleteMedia(ctx context.Context, id int64) (*media.DeleteMediaResponse, error)
+	UpdateMedia(ctx context.Context, media *media.Media, fields []string) (*media.Media, error)
+	TouchMedia(ctx context.Context, id int64, version int64) (time.Time, error)
+	DeleteMedia(ctx context.Context, id int64, version int64) (*media.DeleteMediaResponse, error)
+	ListMedia(ctx context.Context, params ListMediaParams) ([]*media.Media, string, error)
+	ExportMedia(ctx context.Context, afterID int64, filter ExportFilter, limit int) ([]*media.Media, error)
+	RestoreMedia(ctx context.Context, id int64) (*media.Media, error)
+	PurgeMedia(ctx context.Context, id int64) (*media.DeleteMediaResponse, error)
+	PurgeDeletedBefore(ctx context.Context, cutoff time.Time) (int64, error)
+	ClaimOutbox(ctx context.Context, limit int, lease time.Duration) ([]OutboxMessage, error)
+	MarkOutboxSent(ctx context.Context, ids []int64) error
+	MarkOutboxFailed(ctx context.Context, id int64, sendErr error, nextAttemptAt time.Time) error
+	DeleteSentOutbox(ctx context.Context, before time.Time, limit int) (int64, error)
+	ListStaleMedia(ctx context.Context, limit int) ([]*media.Media, error)
+	RecordSyncResult(ctx context.Context, mediaID int64, status SyncStatus, syncErr error) error
+	TryAdvisoryLock(ctx context.Context, name string) (unlock func(), acquired bool, err error)
 }
 
 // PostgresRepository представляет собой реализацию репозитория для PostgreSQL
 type PostgresRepository struct {
-	db     *gorm.DB
-	logger *slog.Logger
+	db               *gorm.DB
+	logger           *slog.Logger
+	trigramThreshold float64
 }
 
-// NewPostgresRepository создает новый экземпляр PostgresRepository
-func NewPostgresRepository(db *gorm.DB, logger *slog.Logger) Repository {
-	return &PostgresRepository{db: db, logger: logger}
+// NewPostgresRepository создает новый экземпляр PostgresRepository.
+// trigramThreshold - минимальное сходство для SearchModeTrigram (0 - значение по умолчанию)
+func NewPostgresRepository(db *gorm.DB, logger *slog.Logger, trigramThreshold float64) Repository {
+	if trigramThreshold <= 0 {
+		trigramThreshold = DefaultTrigramThreshold
+	}
+	return &PostgresRepository{db: db, logger: logger, trigramThreshold: trigramThreshold}
 }
 
 // checkContextCancelled проверяет отмену контекста
 func (r *PostgresRepository) checkContextCancelled(ctx context.Context, action string, params map[string]interface{}) error {
 	select {
 	case <-ctx.Done():
-		r.logger.WarnContext(ctx, fmt.Sprintf("%s cancelled", action), params, "error", ctx.Err())
+		r.logger.WarnContext(ctx, fmt.Sprintf("%s cancelled", action), "params", params, "error", ctx.Err())
 		return fmt.Errorf("%s cancelled: %w", action, ctx.Err())
 	default:
 		return nil
@@ -61,6 +91,10 @@ func (r *PostgresRepository) GetMediaByID(ctx context.Context, id int64) (*media
 	}
 
 	m := convertGormMediaToProtoMedia(&gormMedia)
+	if err := loadReferences(r.db.WithContext(ctx), m); err != nil {
+		r.logger.ErrorContext(ctx, "Failed to load media references", "id", id, "error", err)
+		return nil, err
+	}
 	r.logger.InfoContext(ctx, "Media retrieved successfully", "id", id, "name_en", m.NameEn)
 	return m, nil
 }
@@ -81,21 +115,35 @@ func (r *PostgresRepository) GetMediaByKinopoiskID(ctx context.Context, kinopois
 	}
 
 	m := convertGormMediaToProtoMedia(&gormMedia)
+	if err := loadReferences(r.db.WithContext(ctx), m); err != nil {
+		r.logger.ErrorContext(ctx, "Failed to load media references", "kinopoisk_id", kinopoiskID, "error", err)
+		return nil, err
+	}
 	r.logger.InfoContext(ctx, "Media retrieved successfully by kinopoisk_id", "kinopoisk_id", kinopoiskID, "name_en", m.NameEn)
 	return m, nil
 }
 
-func (r *PostgresRepository) GetMediasByNameFromRepo(ctx context.Context, name string) ([]*media.Media, error) {
-	if err := r.checkContextCancelled(ctx, "GetMediasByName", map[string]interface{}{"name": name}); err != nil {
+// GetMediasByNameFromRepo ищет медиа по названию в локальной базе в заданном режиме
+func (r *PostgresRepository) GetMediasByNameFromRepo(ctx context.Context, name string, mode SearchMode) ([]*media.Media, error) {
+	if err := r.checkContextCancelled(ctx, "GetMediasByName", map[string]interface{}{"name": name, "mode": mode}); err != nil {
 		return nil, err
 	}
 
 	var gormMedias []GormMedia
-	query := r.db.WithContext(ctx).Where("lower(name_en) LIKE ? OR lower(name_ru) LIKE ?", "%"+strings.ToLower(name)+"%", "%"+strings.ToLower(name)+"%")
-	result := query.Find(&gormMedias)
-	if result.Error != nil {
-		r.logger.ErrorContext(ctx, "Failed to get medias by name", "name", name, "error", result.Error)
-		return nil, fmt.Errorf("failed to get medias with name %s: %w", name, result.Error)
+	var err error
+	switch mode {
+	case SearchModeFullText:
+		gormMedias, err = r.searchFullText(ctx, name)
+	case SearchModeLike:
+		gormMedias, err = r.searchLike(ctx, name)
+	case SearchModeTrigram:
+		gormMedias, err = r.searchTrigram(ctx, name)
+	default:
+		return nil, fmt.Errorf("unknown search mode %q", mode)
+	}
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Failed to get medias by name", "name", name, "mode", mode, "error", err)
+		return nil, fmt.Errorf("failed to get medias with name %s: %w", name, err)
 	}
 
 	var medias []*media.Media
@@ -103,8 +151,12 @@ func (r *PostgresRepository) GetMediasByNameFromRepo(ctx context.Context, name s
 		m := convertGormMediaToProtoMedia(&gormMedia)
 		medias = append(medias, m)
 	}
+	if err := loadReferences(r.db.WithContext(ctx), medias...); err != nil {
+		r.logger.ErrorContext(ctx, "Failed to load media references", "name", name, "error", err)
+		return nil, err
+	}
 
-	r.logger.InfoContext(ctx, "Medias retrieved successfully", "name", name, "count", len(medias))
+	r.logger.InfoContext(ctx, "Medias retrieved successfully", "name", name, "mode", mode, "count", len(medias))
 	return medias, nil
 }
 
@@ -113,42 +165,46 @@ func (r *PostgresRepository) CreateMedia(ctx context.Context, media *media.Media
 		return nil, err
 	}
 
+	NormalizeReferences(media)
 	gormMedia := convertProtoMediaToGormMedia(media)
+	gormMedia.Version = 1
 
 	r.logger.InfoContext(ctx, "Creating media", "media_kinopoisk_id", gormMedia.KinopoiskID, "media_name_en", gormMedia.NameEn)
-	result := r.db.WithContext(ctx).Create(&gormMedia)
-	if err := result.Error; err != nil {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&gormMedia).Error; err != nil {
+			return err
+		}
+		media.Id = gormMedia.ID
+		if err := saveReferences(tx, media); err != nil {
+			return err
+		}
+		return recordChange(ctx, tx, gormMedia.ID, HistoryActionCreate, nil, &gormMedia)
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			r.logger.WarnContext(ctx, "Media with kinopoisk_id already exists", "media_kinopoisk_id", gormMedia.KinopoiskID)
+			return nil, fmt.Errorf("failed to create media with kinopoisk_id %d: %w", gormMedia.KinopoiskID, ErrDuplicateKinopoiskID)
+		}
 		r.logger.ErrorContext(ctx, "Failed to create media", "media_kinopoisk_id", gormMedia.KinopoiskID, "media_name_en", gormMedia.NameEn, "error", err)
 		return nil, fmt.Errorf("failed to create media with kinopoisk_id %d: %w", gormMedia.KinopoiskID, err)
 	}
 
 	createdMedia := convertGormMediaToProtoMedia(&gormMedia)
+	createdMedia.GenreList = media.GenreList
+	createdMedia.CountryList = media.CountryList
 
 	r.logger.InfoContext(ctx, "Media created successfully", "media_kinopoisk_id", gormMedia.KinopoiskID, "media_name_en", gormMedia.NameEn)
 	return createdMedia, nil
 }
 
-func (r *PostgresRepository) UpdateMedia(ctx context.Context, media *media.Media) (*media.Media, error) {
+// UpdateMedia обновляет медиа. Если fields не пуст, обновляются только эти колонки.
+// Строка блокируется на время транзакции, чтобы история содержала точное состояние до изменения
+func (r *PostgresRepository) UpdateMedia(ctx context.Context, media *media.Media, fields []string) (*media.Media, error) {
 	if err := r.checkContextCancelled(ctx, "UpdateMedia", map[string]interface{}{"id": media.Id}); err != nil {
 		return nil, err
 	}
 
-	var existingMedia GormMedia
-	if err := r.db.WithContext(ctx).First(&existingMedia, media.Id).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, ErrMediaNotFound
-		}
-		r.logger.ErrorContext(ctx, "Failed to get media by ID", "id", media.Id, "error", err)
-		return nil, fmt.Errorf("failed to get media with id %d: %w", media.Id, err)
-	}
-
-	r.logger.InfoContext(ctx, "Existing media found for update", "id", media.Id, "existing_kinopoisk_id", existingMedia.KinopoiskID, "existing_name_en", existingMedia.NameEn)
-
-	if media.KinopoiskId != existingMedia.KinopoiskID {
-		r.logger.ErrorContext(ctx, "kinopoisk_id mismatch", "id", media.Id, "request_kinopoisk_id", media.KinopoiskId, "db_kinopoisk_id", existingMedia.KinopoiskID)
-		return nil, fmt.Errorf("kinopoisk_id mismatch: cannot update media with a different kinopoisk_id")
-	}
-
+	NormalizeReferences(media)
 	gormUpdates := convertProtoMediaToGormMedia(media)
 	updates := map[string]interface{}{
 		"type":        gormUpdates.Type,
@@ -161,42 +217,128 @@ func (r *PostgresRepository) UpdateMedia(ctx context.Context, media *media.Media
 		"genres":      gormUpdates.Genres,
 	}
 
-	r.logger.InfoContext(ctx, "Updating media fields", "id", media.Id, "updated_fields", updates)
+	if len(fields) > 0 {
+		selected := make(map[string]interface{}, len(fields))
+		for _, field := range fields {
+			column := field
+			switch field {
+			case "genre_list":
+				column = "genres"
+			case "country_list":
+				column = "countries"
+			}
+			value, ok := updates[column]
+			if !ok {
+				return nil, fmt.Errorf("unknown field %q in update mask", field)
+			}
+			selected[column] = value
+		}
+		updates = selected
+	}
+	_, updateGenres := updates["genres"]
+	_, updateCountries := updates["countries"]
+
+	r.logger.InfoContext(ctx, "Updating media fields", "id", media.Id, "version", media.Version, "updated_fields", updates)
+
+	// Условная запись: обновляем, только если версия не изменилась, и сразу получаем новую строку
+	updates["version"] = gorm.Expr("version + 1")
+	var updated GormMedia
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existingMedia GormMedia
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&existingMedia, media.Id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrMediaNotFound
+			}
+			return fmt.Errorf("failed to get media with id %d: %w", media.Id, err)
+		}
+
+		r.logger.InfoContext(ctx, "Existing media found for update", "id", media.Id, "existing_kinopoisk_id", existingMedia.KinopoiskID, "existing_name_en", existingMedia.NameEn)
 
-	if err := r.db.WithContext(ctx).Model(&existingMedia).Updates(updates).Error; err != nil {
-		r.logger.ErrorContext(ctx, "Failed to update media", "id", media.Id, "error", err)
+		if media.KinopoiskId != existingMedia.KinopoiskID {
+			r.logger.ErrorContext(ctx, "kinopoisk_id mismatch", "id", media.Id, "request_kinopoisk_id", media.KinopoiskId, "db_kinopoisk_id", existingMedia.KinopoiskID)
+			return fmt.Errorf("%w: cannot update media with a different kinopoisk_id", ErrKinopoiskIDMismatch)
+		}
+
+		result := tx.Model(&updated).Clauses(clause.Returning{}).
+			Where("id = ? AND version = ?", media.Id, media.Version).
+			Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			r.logger.WarnContext(ctx, "Media version conflict", "id", media.Id, "request_version", media.Version, "db_version", existingMedia.Version)
+			return fmt.Errorf("%w: media with id %d is not at version %d", ErrVersionConflict, media.Id, media.Version)
+		}
+		if updateGenres {
+			if err := replaceLinks(tx, genresTable, media.Id, media.GenreList); err != nil {
+				return err
+			}
+		}
+		if updateCountries {
+			if err := replaceLinks(tx, countriesTable, media.Id, media.CountryList); err != nil {
+				return err
+			}
+		}
+		return recordChange(ctx, tx, media.Id, HistoryActionUpdate, &existingMedia, &updated)
+	})
+	if err != nil {
+		if errors.Is(err, ErrMediaNotFound) {
+			return nil, ErrMediaNotFound
+		}
+		if !errors.Is(err, ErrVersionConflict) && !errors.Is(err, ErrKinopoiskIDMismatch) {
+			r.logger.ErrorContext(ctx, "Failed to update media", "id", media.Id, "error", err)
+		}
 		return nil, fmt.Errorf("failed to update media with id %d: %w", media.Id, err)
 	}
 
-	updatedMedia, err := r.GetMediaByID(ctx, media.Id)
-	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve updated media with id %d: %w", media.Id, err)
+	updatedMedia := convertGormMediaToProtoMedia(&updated)
+	if err := loadReferences(r.db.WithContext(ctx), updatedMedia); err != nil {
+		return nil, err
 	}
 
-	r.logger.InfoContext(ctx, "Successfully updated media", "id", updatedMedia.Id, "kinopoisk_id", updatedMedia.KinopoiskId, "updated_name_en", updatedMedia.NameEn, "updated_name_ru", updatedMedia.NameRu)
+	r.logger.InfoContext(ctx, "Successfully updated media", "id", updatedMedia.Id, "kinopoisk_id", updatedMedia.KinopoiskId, "version", updatedMedia.Version, "updated_name_en", updatedMedia.NameEn, "updated_name_ru", updatedMedia.NameRu)
 
 	return updatedMedia, nil
 }
 
-func (r *PostgresRepository) DeleteMedia(ctx context.Context, id int64) (*media.DeleteMediaResponse, error) {
+// DeleteMedia мягко удаляет медиа, если его версия совпадает с ожидаемой
+func (r *PostgresRepository) DeleteMedia(ctx context.Context, id int64, version int64) (*media.DeleteMediaResponse, error) {
 	if err := r.checkContextCancelled(ctx, "DeleteMedia", map[string]interface{}{"id": id}); err != nil {
 		return nil, err
 	}
 
-	var existingMedia GormMedia
-	result := r.db.WithContext(ctx).First(&existingMedia, id)
-	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existingMedia GormMedia
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&existingMedia, id)
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return ErrMediaNotFound
+		}
+		if result.Error != nil {
+			return fmt.Errorf("failed to find media with id %d: %w", id, result.Error)
+		}
+
+		// Мягко удаляем медиа, только если версия не изменилась
+		var deleted GormMedia
+		result = tx.Model(&deleted).Clauses(clause.Returning{}).
+			Where("id = ? AND version = ?", id, version).
+			Updates(map[string]interface{}{"deleted_at": time.Now(), "version": gorm.Expr("version + 1")})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			r.logger.WarnContext(ctx, "Media version conflict on delete", "id", id, "request_version", version, "db_version", existingMedia.Version)
+			return fmt.Errorf("%w: media with id %d is not at version %d", ErrVersionConflict, id, version)
+		}
+		return recordChange(ctx, tx, id, HistoryActionDelete, &existingMedia, &deleted)
+	})
+	if errors.Is(err, ErrMediaNotFound) {
 		r.logger.InfoContext(ctx, "Media not found, nothing to delete", "id", id)
-		return &media.DeleteMediaResponse{Success: false}, nil
-	}
-	if result.Error != nil {
-		r.logger.ErrorContext(ctx, "Failed to find media by ID", "id", id, "error", result.Error)
-		return nil, fmt.Errorf("failed to find media with id %d: %w", id, result.Error)
+		return nil, fmt.Errorf("media with id %d: %w", id, err)
 	}
-
-	// Удаляем медиа из базы данных
-	if err := r.db.WithContext(ctx).Delete(&existingMedia).Error; err != nil {
-		r.logger.ErrorContext(ctx, "Failed to delete media", "id", id, "error", err)
+	if err != nil {
+		if !errors.Is(err, ErrVersionConflict) {
+			r.logger.ErrorContext(ctx, "Failed to delete media", "id", id, "error", err)
+		}
 		return nil, fmt.Errorf("failed to delete media with id %d: %w", id, err)
 	}
 
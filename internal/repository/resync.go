@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"time"
+
+	"github.com/watchlist-kata/protos/media"
+	"gorm.io/gorm/clause"
+)
+
+// SyncStatus - результат сверки медиа с Кинопоиском
+type SyncStatus string
+
+const (
+	SyncStatusUpdated   SyncStatus = "updated"
+	SyncStatusUnchanged SyncStatus = "unchanged"
+	SyncStatusFailed    SyncStatus = "failed"
+)
+
+// GormMediaSync - результат последней сверки медиа с Кинопоиском
+type GormMediaSync struct {
+	MediaID   int64     `gorm:"primaryKey"`
+	Status    string    `gorm:"type:varchar(20);not null"`
+	Error     string    `gorm:"type:text;not null"`
+	CheckedAt time.Time `gorm:"not null"`
+}
+
+// TableName возвращает имя таблицы для GORM
+func (GormMediaSync) TableName() string {
+	return "media_sync"
+}
+
+// ListStaleMedia возвращает до limit медиа из Кинопоиска, которые дольше всех не обновлялись.
+// Время последней неудачной сверки тоже учитывается, чтобы ошибки не занимали всю выборку
+func (r *PostgresRepository) ListStaleMedia(ctx context.Context, limit int) ([]*media.Media, error) {
+	if err := r.checkContextCancelled(ctx, "ListStaleMedia", map[string]interface{}{"limit": limit}); err != nil {
+		return nil, err
+	}
+
+	var gormMedias []GormMedia
+	err := r.db.WithContext(ctx).
+		Select("media.*").
+		Joins("LEFT JOIN media_sync ON media_sync.media_id = media.id").
+		Where("media.kinopoisk_id > 0").
+		Order("GREATEST(media.updated_at, COALESCE(media_sync.checked_at, media.updated_at)), media.id").
+		Limit(limit).
+		Find(&gormMedias).Error
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Failed to list stale media", "limit", limit, "error", err)
+		return nil, fmt.Errorf("failed to list stale media: %w", err)
+	}
+
+	medias := make([]*media.Media, 0, len(gormMedias))
+	for i := range gormMedias {
+		medias = append(medias, convertGormMediaToProtoMedia(&gormMedias[i]))
+	}
+	return medias, nil
+}
+
+// RecordSyncResult сохраняет результат сверки медиа с Кинопоиском
+func (r *PostgresRepository) RecordSyncResult(ctx context.Context, mediaID int64, status SyncStatus, syncErr error) error {
+	if err := r.checkContextCancelled(ctx, "RecordSyncResult", map[string]interface{}{"media_id": mediaID, "status": status}); err != nil {
+		return err
+	}
+
+	result := GormMediaSync{MediaID: mediaID, Status: string(status), CheckedAt: time.Now()}
+	if syncErr != nil {
+		result.Error = syncErr.Error()
+	}
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "media_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"status", "error", "checked_at"}),
+	}).Create(&result).Error
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Failed to record sync result", "media_id", mediaID, "status", status, "error", err)
+		return fmt.Errorf("failed to record sync result for media %d: %w", mediaID, err)
+	}
+	return nil
+}
+
+// TryAdvisoryLock пытается взять сессионную advisory-блокировку Postgres с именем name
+// на выделенном соединении. Если блокировка взята, unlock освобождает ее и соединение
+func (r *PostgresRepository) TryAdvisoryLock(ctx context.Context, name string) (unlock func(), acquired bool, err error) {
+	sqlDB, err := r.db.DB()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get database handle: %w", err)
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock(hashtext($1))", name).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, false, fmt.Errorf("failed to acquire advisory lock %s: %w", name, err)
+	}
+	if !acquired {
+		conn.Close()
+		return nil, false, nil
+	}
+
+	unlock = func() {
+		// Контекст задачи к этому моменту может быть отменен, а блокировку нужно снять
+		unlockCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if _, err := conn.ExecContext(unlockCtx, "SELECT pg_advisory_unlock(hashtext($1))", name); err != nil {
+			r.logger.ErrorContext(ctx, "Failed to release advisory lock, dropping connection", "name", name, "error", err)
+			// Соединение с блокировкой не должно вернуться в пул
+			_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+		}
+		conn.Close()
+	}
+	return unlock, true, nil
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// SearchMode определяет способ поиска по названию в локальной базе
+type SearchMode string
+
+const (
+	// SearchModeFullText - полнотекстовый поиск по search_vector с сортировкой по ts_rank
+	SearchModeFullText SearchMode = "fulltext"
+	// SearchModeLike - поиск подстроки в name_ru/name_en через LIKE
+	SearchModeLike SearchMode = "like"
+	// SearchModeTrigram - нечеткий поиск по сходству триграмм (pg_trgm), устойчивый к опечаткам
+	SearchModeTrigram SearchMode = "trigram"
+)
+
+// DefaultTrigramThreshold - минимальное сходство для SearchModeTrigram по умолчанию
+const DefaultTrigramThreshold = 0.3
+
+// ParseSearchMode преобразует строку в SearchMode
+func ParseSearchMode(value string) (SearchMode, error) {
+	switch mode := SearchMode(strings.ToLower(value)); mode {
+	case SearchModeFullText, SearchModeLike, SearchModeTrigram:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown search mode %q", value)
+	}
+}
+
+// searchFullText ищет по взвешенному tsvector (названия важнее описания) с русской и английской морфологией
+func (r *PostgresRepository) searchFullText(ctx context.Context, name string) ([]GormMedia, error) {
+	var gormMedias []GormMedia
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT media.*
+		FROM media
+		CROSS JOIN (SELECT websearch_to_tsquery('russian', @name) || websearch_to_tsquery('english', @name) AS query) q
+		WHERE media.search_vector @@ q.query AND media.deleted_at IS NULL
+		ORDER BY ts_rank(media.search_vector, q.query) DESC, media.id`,
+		map[string]interface{}{"name": name},
+	).Scan(&gormMedias).Error
+	return gormMedias, err
+}
+
+// searchLike ищет подстроку в названиях без учета регистра
+func (r *PostgresRepository) searchLike(ctx context.Context, name string) ([]GormMedia, error) {
+	var gormMedias []GormMedia
+	pattern := "%" + strings.ToLower(name) + "%"
+	err := r.db.WithContext(ctx).Where("lower(name_en) LIKE ? OR lower(name_ru) LIKE ?", pattern, pattern).Find(&gormMedias).Error
+	return gormMedias, err
+}
+
+// searchTrigram ищет названия, похожие на name не меньше чем на trigramThreshold.
+// Порог задается через pg_trgm.similarity_threshold, чтобы оператор % использовал GIN индексы
+func (r *PostgresRepository) searchTrigram(ctx context.Context, name string) ([]GormMedia, error) {
+	var gormMedias []GormMedia
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		threshold := fmt.Sprintf("%g", r.trigramThreshold)
+		if err := tx.Exec("SELECT set_config('pg_trgm.similarity_threshold', ?, true)", threshold).Error; err != nil {
+			return fmt.Errorf("failed to set similarity threshold: %w", err)
+		}
+		return tx.Raw(`
+			SELECT media.*
+			FROM media
+			WHERE (media.name_ru % @name OR media.name_en % @name) AND media.deleted_at IS NULL
+			ORDER BY greatest(similarity(media.name_ru, @name), similarity(media.name_en, @name)) DESC, media.id`,
+			map[string]interface{}{"name": name},
+		).Scan(&gormMedias).Error
+	})
+	return gormMedias, err
+}
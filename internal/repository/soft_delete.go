@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/watchlist-kata/protos/media"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RestoreMedia восстанавливает мягко удаленное медиа
+func (r *PostgresRepository) RestoreMedia(ctx context.Context, id int64) (*media.Media, error) {
+	if err := r.checkContextCancelled(ctx, "RestoreMedia", map[string]interface{}{"id": id}); err != nil {
+		return nil, err
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existingMedia GormMedia
+		if err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("deleted_at IS NOT NULL").First(&existingMedia, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("deleted media with id %d: %w", id, ErrMediaNotFound)
+			}
+			return err
+		}
+
+		var restored GormMedia
+		result := tx.Unscoped().Model(&restored).Clauses(clause.Returning{}).
+			Where("id = ?", id).
+			Updates(map[string]interface{}{"deleted_at": nil, "version": gorm.Expr("version + 1")})
+		if result.Error != nil {
+			return result.Error
+		}
+		return recordChange(ctx, tx, id, HistoryActionRestore, &existingMedia, &restored)
+	})
+	if err != nil {
+		if errors.Is(err, ErrMediaNotFound) {
+			r.logger.InfoContext(ctx, "No deleted media to restore", "id", id)
+			return nil, err
+		}
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			// Пока медиа было удалено, тот же фильм импортировали заново
+			r.logger.WarnContext(ctx, "Media with kinopoisk_id already exists, cannot restore", "id", id)
+			return nil, fmt.Errorf("failed to restore media with id %d: %w", id, ErrDuplicateKinopoiskID)
+		}
+		r.logger.ErrorContext(ctx, "Failed to restore media", "id", id, "error", err)
+		return nil, fmt.Errorf("failed to restore media with id %d: %w", id, err)
+	}
+
+	r.logger.InfoContext(ctx, "Media restored successfully", "id", id)
+	return r.GetMediaByID(ctx, id)
+}
+
+// PurgeMedia окончательно удаляет медиа, в том числе мягко удаленное
+func (r *PostgresRepository) PurgeMedia(ctx context.Context, id int64) (*media.DeleteMediaResponse, error) {
+	if err := r.checkContextCancelled(ctx, "PurgeMedia", map[string]interface{}{"id": id}); err != nil {
+		return nil, err
+	}
+
+	var purged []GormMedia
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Clauses(clause.Returning{}).Where("id = ?", id).Delete(&purged).Error; err != nil {
+			return err
+		}
+		for i := range purged {
+			if err := recordChange(ctx, tx, purged[i].ID, HistoryActionPurge, &purged[i], nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Failed to purge media", "id", id, "error", err)
+		return nil, fmt.Errorf("failed to purge media with id %d: %w", id, err)
+	}
+	if len(purged) == 0 {
+		r.logger.InfoContext(ctx, "Media not found, nothing to purge", "id", id)
+		return &media.DeleteMediaResponse{Success: false}, nil
+	}
+
+	r.logger.InfoContext(ctx, "Media purged successfully", "id", id)
+	return &media.DeleteMediaResponse{Success: true}, nil
+}
+
+// PurgeDeletedBefore окончательно удаляет медиа, мягко удаленные раньше cutoff
+func (r *PostgresRepository) PurgeDeletedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	if err := r.checkContextCancelled(ctx, "PurgeDeletedBefore", map[string]interface{}{"cutoff": cutoff}); err != nil {
+		return 0, err
+	}
+
+	var purged []GormMedia
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Clauses(clause.Returning{}).
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+			Delete(&purged).Error; err != nil {
+			return err
+		}
+		for i := range purged {
+			if err := recordChange(ctx, tx, purged[i].ID, HistoryActionPurge, &purged[i], nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Failed to purge deleted media", "cutoff", cutoff, "error", err)
+		return 0, fmt.Errorf("failed to purge media deleted before %s: %w", cutoff.Format(time.RFC3339), err)
+	}
+
+	r.logger.InfoContext(ctx, "Deleted media purged", "cutoff", cutoff, "count", len(purged))
+	return int64(len(purged)), nil
+}
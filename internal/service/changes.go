@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/watchlist-kata/media/internal/validation"
+	"github.com/watchlist-kata/protos/media"
+)
+
+const (
+	// watchBatchSize - сколько записей истории читать за раз
+	watchBatchSize = 500
+	// watchPollInterval - как часто проверять историю без уведомлений,
+	// чтобы получить изменения других реплик и фоновых задач
+	watchPollInterval = 5 * time.Second
+	// watchGapRetry и watchGapTimeout - ожидание пропуска в номерах истории:
+	// транзакция с меньшим id могла еще не завершиться. Если пропуск не заполнился
+	// за watchGapTimeout, транзакция считается откатившейся
+	watchGapRetry   = 200 * time.Millisecond
+	watchGapTimeout = 2 * time.Second
+)
+
+// ErrShuttingDown - подписка закрыта, потому что сервис останавливается
+var ErrShuttingDown = errors.New("service is shutting down")
+
+// ChangeBroadcaster уведомляет подписчиков внутри процесса об изменениях медиа
+type ChangeBroadcaster struct {
+	mu          sync.Mutex
+	subscribers map[*changeSubscription]struct{}
+	closed      chan struct{} // Закрывается при остановке, подписки завершаются
+	closeOnce   sync.Once
+}
+
+// changeSubscription - подписка на изменения. Уведомления схлопываются:
+// подписчик перечитывает историю, поэтому достаточно одного сигнала
+type changeSubscription struct {
+	notify   chan struct{}
+	mediaIDs map[int64]struct{} // Пусто - все медиа
+}
+
+// NewChangeBroadcaster создает новый ChangeBroadcaster
+func NewChangeBroadcaster() *ChangeBroadcaster {
+	return &ChangeBroadcaster{
+		subscribers: make(map[*changeSubscription]struct{}),
+		closed:      make(chan struct{}),
+	}
+}
+
+// Close завершает все подписки, чтобы потоковые вызовы не задерживали остановку сервера
+func (b *ChangeBroadcaster) Close() {
+	b.closeOnce.Do(func() { close(b.closed) })
+}
+
+// Publish уведомляет подписчиков об изменении медиа, не блокируясь на медленных подписчиках
+func (b *ChangeBroadcaster) Publish(mediaID int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for sub := range b.subscribers {
+		if len(sub.mediaIDs) > 0 {
+			if _, ok := sub.mediaIDs[mediaID]; !ok {
+				continue
+			}
+		}
+		select {
+		case sub.notify <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// subscribe добавляет подписку на изменения mediaIDs (пусто - всех медиа)
+func (b *ChangeBroadcaster) subscribe(mediaIDs []int64) *changeSubscription {
+	sub := &changeSubscription{
+		notify:   make(chan struct{}, 1),
+		mediaIDs: make(map[int64]struct{}, len(mediaIDs)),
+	}
+	for _, id := range mediaIDs {
+		sub.mediaIDs[id] = struct{}{}
+	}
+	b.mu.Lock()
+	b.subscribers[sub] = struct{}{}
+	b.mu.Unlock()
+	return sub
+}
+
+// unsubscribe удаляет подписку
+func (b *ChangeBroadcaster) unsubscribe(sub *changeSubscription) {
+	b.mu.Lock()
+	delete(b.subscribers, sub)
+	b.mu.Unlock()
+}
+
+// Close завершает подписки на изменения и дожидается фоновых обновлений
+func (s *MediaService) Close() {
+	s.changes.Close()
+	s.stopRefresh()
+	s.wg.Wait()
+}
+
+// publishChange уведомляет подписчиков об успешной записи
+func (s *MediaService) publishChange(m *media.Media) {
+	if m != nil && m.Id > 0 {
+		s.changes.Publish(m.Id)
+	}
+}
+
+// WatchMediaChanges отправляет записи истории изменений до отмены контекста или ошибки send.
+// Сначала отправляются сохраненные изменения после req.AfterSequence или req.Since,
+// затем новые по мере появления
+func (s *MediaService) WatchMediaChanges(ctx context.Context, req *media.WatchMediaChangesRequest, send func(*media.MediaHistoryEntry) error) error {
+	if req == nil {
+		return fmt.Errorf("invalid request: nil pointer")
+	}
+
+	s.logger.InfoContext(ctx, "WatchMediaChanges called", "media_ids", req.MediaIds, "after_sequence", req.AfterSequence, "since", req.Since)
+
+	verr := &validation.Error{}
+	if req.AfterSequence < 0 {
+		verr.Add("after_sequence", "must not be negative")
+	}
+	var since time.Time
+	if req.Since != "" {
+		var err error
+		if since, err = time.Parse(time.RFC3339, req.Since); err != nil {
+			verr.Add("since", "must be an RFC3339 timestamp")
+		}
+	}
+	for _, id := range req.MediaIds {
+		if id <= 0 {
+			verr.Add("media_ids", "must be greater than 0")
+			break
+		}
+	}
+	if err := verr.Err(); err != nil {
+		return err
+	}
+
+	// Подписываемся до чтения истории, чтобы не пропустить изменения между чтением и подпиской
+	sub := s.changes.subscribe(req.MediaIds)
+	defer s.changes.unsubscribe(sub)
+
+	sequence := req.AfterSequence
+	if sequence == 0 {
+		var err error
+		// Без since отправляются только новые изменения
+		if sequence, err = s.repo.GetHistorySequence(ctx, since); err != nil {
+			return s.handleError(ctx, "Failed to WatchMediaChanges", fmt.Errorf("failed to resolve start sequence: %w", err), "error", err)
+		}
+	}
+
+	poll := time.NewTicker(watchPollInterval)
+	defer poll.Stop()
+
+	var gapSince time.Time
+	for {
+		var err error
+		sequence, gapSince, err = s.sendHistoryAfter(ctx, sequence, gapSince, sub.mediaIDs, send)
+		if err != nil {
+			return err
+		}
+
+		var gapRetry <-chan time.Time
+		if !gapSince.IsZero() {
+			gapRetry = time.After(watchGapRetry)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.changes.closed:
+			return ErrShuttingDown
+		case <-sub.notify:
+		case <-poll.C:
+		case <-gapRetry:
+		}
+	}
+}
+
+// sendHistoryAfter отправляет записи истории после sequence и возвращает номер последней
+// обработанной записи. При пропуске в номерах отправка останавливается, пока пропуск
+// не заполнится или не истечет watchGapTimeout
+func (s *MediaService) sendHistoryAfter(ctx context.Context, sequence int64, gapSince time.Time, mediaIDs map[int64]struct{}, send func(*media.MediaHistoryEntry) error) (int64, time.Time, error) {
+	for {
+		entries, err := s.repo.ListHistoryAfter(ctx, sequence, watchBatchSize)
+		if err != nil {
+			return sequence, gapSince, s.handleError(ctx, "Failed to WatchMediaChanges", fmt.Errorf("failed to read media history: %w", err), "sequence", sequence, "error", err)
+		}
+
+		for _, entry := range entries {
+			if entry.Id != sequence+1 && !settled(entry) {
+				if gapSince.IsZero() {
+					gapSince = time.Now()
+				}
+				if time.Since(gapSince) < watchGapTimeout {
+					return sequence, gapSince, nil
+				}
+			}
+			if entry.Id != sequence+1 {
+				s.logger.DebugContext(ctx, "Skipping gap in media history", "after", sequence, "next", entry.Id)
+			}
+			gapSince = time.Time{}
+
+			if _, ok := mediaIDs[entry.MediaId]; ok || len(mediaIDs) == 0 {
+				if err := send(entry); err != nil {
+					return sequence, gapSince, err
+				}
+			}
+			sequence = entry.Id
+		}
+
+		if len(entries) < watchBatchSize {
+			return sequence, gapSince, nil
+		}
+	}
+}
+
+// settled сообщает, что запись создана давно и транзакции с меньшими id уже завершились,
+// поэтому пропуск перед ней при чтении старой истории ждать не нужно
+func settled(entry *media.MediaHistoryEntry) bool {
+	createdAt, err := time.Parse(time.RFC3339, entry.CreatedAt)
+	return err == nil && time.Since(createdAt) > 2*watchGapTimeout
+}
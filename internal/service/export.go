@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/watchlist-kata/media/internal/repository"
+	"github.com/watchlist-kata/media/internal/validation"
+	"github.com/watchlist-kata/protos/media"
+)
+
+// exportBatchSize - сколько медиа читать из базы за раз при выгрузке
+const exportBatchSize = 500
+
+// ExportMedia отправляет медиа в порядке ID, читая таблицу страницами по ID.
+// Фильтры по updated_at позволяют выгружать только изменения с прошлой выгрузки
+func (s *MediaService) ExportMedia(ctx context.Context, req *media.ExportMediaRequest, send func(*media.Media) error) error {
+	if req == nil {
+		return fmt.Errorf("invalid request: nil pointer")
+	}
+
+	s.logger.InfoContext(ctx, "ExportMedia called", "updated_since", req.UpdatedSince, "updated_before", req.UpdatedBefore, "after_id", req.AfterId, "include_deleted", req.IncludeDeleted)
+
+	verr := &validation.Error{}
+	filter := repository.ExportFilter{IncludeDeleted: req.IncludeDeleted}
+	if req.UpdatedSince != "" {
+		var err error
+		if filter.UpdatedSince, err = time.Parse(time.RFC3339, req.UpdatedSince); err != nil {
+			verr.Add("updated_since", "must be an RFC3339 timestamp")
+		}
+	}
+	if req.UpdatedBefore != "" {
+		var err error
+		if filter.UpdatedBefore, err = time.Parse(time.RFC3339, req.UpdatedBefore); err != nil {
+			verr.Add("updated_before", "must be an RFC3339 timestamp")
+		}
+	}
+	if req.AfterId < 0 {
+		verr.Add("after_id", "must not be negative")
+	}
+	if err := verr.Err(); err != nil {
+		return err
+	}
+
+	afterID, exported := req.AfterId, 0
+	for {
+		medias, err := s.repo.ExportMedia(ctx, afterID, filter, exportBatchSize)
+		if err != nil {
+			return s.handleError(ctx, "Failed to ExportMedia", fmt.Errorf("failed to export media after id %d: %w", afterID, err), "after_id", afterID, "error", err)
+		}
+		for _, m := range medias {
+			if err := send(m); err != nil {
+				return err
+			}
+			afterID = m.Id
+		}
+		exported += len(medias)
+		if len(medias) < exportBatchSize {
+			break
+		}
+	}
+
+	s.logger.InfoContext(ctx, "ExportMedia successful", "exported", exported, "last_id", afterID)
+	return nil
+}
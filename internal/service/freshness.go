@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/watchlist-kata/media/internal/audit"
+	"github.com/watchlist-kata/protos/media"
+)
+
+// refreshTimeout ограничивает фоновое обновление одного медиа
+const refreshTimeout = 30 * time.Second
+
+// Пул фонового обновления: сколько медиа обновляется одновременно
+// и сколько может ждать в очереди. Лишние обновления отбрасываются
+const (
+	refreshWorkers   = 2
+	refreshQueueSize = 100
+)
+
+// refreshTask - медиа, поставленное в очередь фонового обновления
+type refreshTask struct {
+	id          int64
+	kinopoiskID int64
+	requestID   string // ID запроса, который обнаружил устаревшие данные
+}
+
+// fresh сообщает, что медиа обновлялось из Кинопоиска не раньше freshnessTTL назад
+// и запрашивать Кинопоиск для него не нужно. При выключенной проверке всегда false
+func (s *MediaService) fresh(m *media.Media) bool {
+	if s.freshnessTTL <= 0 {
+		return false
+	}
+	updatedAt, err := time.Parse(time.RFC3339, m.UpdatedAt)
+	return err == nil && time.Since(updatedAt) < s.freshnessTTL
+}
+
+// stale сообщает, что сохраненное медиа пора сверить с Кинопоиском.
+// При выключенной проверке всегда false
+func (s *MediaService) stale(m *media.Media) bool {
+	return s.freshnessTTL > 0 && m.Id > 0 && !s.fresh(m)
+}
+
+// scheduleRefresh ставит медиа в очередь фонового обновления из Кинопоиска, не дожидаясь результата.
+// Для одного медиа в очереди не больше одного обновления, при заполненной очереди обновление пропускается
+func (s *MediaService) scheduleRefresh(ctx context.Context, m *media.Media) {
+	if m.KinopoiskId <= 0 {
+		return
+	}
+
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+	if _, ok := s.refreshing[m.Id]; ok || s.refreshCtx.Err() != nil {
+		return
+	}
+
+	select {
+	case s.refreshQueue <- refreshTask{id: m.Id, kinopoiskID: m.KinopoiskId, requestID: audit.RequestIDFrom(ctx)}:
+		s.refreshing[m.Id] = struct{}{}
+		s.logger.InfoContext(ctx, "Scheduled background refresh of stale media", "id", m.Id, "kinopoiskID", m.KinopoiskId, "updated_at", m.UpdatedAt)
+	default:
+		s.logger.WarnContext(ctx, "Background refresh queue is full, skipping stale media", "id", m.Id, "kinopoiskID", m.KinopoiskId)
+	}
+}
+
+// startRefreshWorkers запускает обработчиков очереди фонового обновления до остановки сервиса
+func (s *MediaService) startRefreshWorkers() {
+	for i := 0; i < refreshWorkers; i++ {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			for {
+				select {
+				case <-s.refreshCtx.Done():
+					return
+				case task := <-s.refreshQueue:
+					s.refresh(task)
+				}
+			}
+		}()
+	}
+}
+
+// refresh обновляет медиа из очереди, соблюдая общий лимит запросов к Кинопоиску.
+// Обновление перезаписывает все поля данными Кинопоиска, в том числе ручные правки
+func (s *MediaService) refresh(task refreshTask) {
+	defer func() {
+		s.refreshMu.Lock()
+		delete(s.refreshing, task.id)
+		s.refreshMu.Unlock()
+	}()
+
+	// Обновление переживает запрос, но сохраняет его ID для истории изменений
+	ctx := audit.WithRequestID(s.refreshCtx, task.requestID)
+	ctx = audit.WithActor(ctx, audit.ActorKinopoiskSync)
+	if err := s.limiter.Wait(ctx); err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, refreshTimeout)
+	defer cancel()
+	if _, err := s.RefreshMedia(ctx, task.kinopoiskID); err != nil {
+		s.logger.WarnContext(ctx, "Background refresh failed", "id", task.id, "kinopoiskID", task.kinopoiskID, "error", err)
+	}
+}
+
+// touchMedia отмечает медиа сверенным с Кинопоиском без изменений, чтобы оно снова считалось свежим.
+// Ошибка не мешает вернуть медиа, поэтому только логируется
+func (s *MediaService) touchMedia(ctx context.Context, m *media.Media) {
+	updatedAt, err := s.repo.TouchMedia(ctx, m.Id, m.Version)
+	if err != nil {
+		s.logger.WarnContext(ctx, "Failed to mark media as checked", "id", m.Id, "error", err)
+		return
+	}
+	m.UpdatedAt = updatedAt.Format(time.RFC3339)
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/watchlist-kata/protos/media"
+)
+
+// Веса составляющих оценки релевантности
+const (
+	scoreExactName   = 100.0 // Название совпадает с запросом
+	scorePrefixName  = 50.0  // Название начинается с запроса
+	scoreContainName = 20.0  // Запрос встречается внутри названия
+	scoreTokens      = 30.0  // Доля слов запроса, найденных в названии
+	scoreYear        = 20.0  // Год совпадает с годом из запроса, за каждый год разницы -scoreYearStep
+	scoreYearStep    = 5.0
+	scoreLocal       = 5.0 // Запись из локальной базы при прочих равных выше записи из Кинопоиска
+)
+
+// relevanceQuery - разобранный поисковый запрос
+type relevanceQuery struct {
+	text   string   // Нормализованный запрос без года
+	tokens []string // Слова запроса без года
+	year   int      // Год из запроса, 0 - не указан
+}
+
+// newRelevanceQuery разбирает запрос. Год выделяется из запроса, только если кроме него
+// есть другие слова, чтобы поиск "1984" искал фильм с таким названием
+func newRelevanceQuery(name string) relevanceQuery {
+	tokens := tokenize(name)
+	q := relevanceQuery{text: strings.Join(tokens, " "), tokens: tokens}
+	if len(tokens) < 2 {
+		return q
+	}
+
+	rest := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		if year := parseYear(token); year > 0 && q.year == 0 {
+			q.year = year
+			continue
+		}
+		rest = append(rest, token)
+	}
+	if q.year > 0 {
+		q.text, q.tokens = strings.Join(rest, " "), rest
+	}
+	return q
+}
+
+// score оценивает релевантность медиа запросу: совпадение названия, пересечение слов,
+// близость года и источник. Берется лучшее из русского и английского названий
+func (q relevanceQuery) score(m *media.Media, source media.MediaSource) float64 {
+	score := max(q.nameScore(m.NameRu), q.nameScore(m.NameEn))
+
+	if q.year > 0 {
+		if diff := yearDistance(m.Year, q.year); diff >= 0 {
+			score += max(scoreYear-scoreYearStep*float64(diff), 0)
+		}
+	}
+	if source == media.MediaSource_MEDIA_SOURCE_LOCAL {
+		score += scoreLocal
+	}
+	return score
+}
+
+// nameScore оценивает совпадение одного названия с запросом
+func (q relevanceQuery) nameScore(name string) float64 {
+	nameTokens := tokenize(name)
+	if len(nameTokens) == 0 || len(q.tokens) == 0 {
+		return 0
+	}
+	text := strings.Join(nameTokens, " ")
+
+	var score float64
+	switch {
+	case text == q.text:
+		score = scoreExactName
+	case strings.HasPrefix(text, q.text):
+		score = scorePrefixName
+	case strings.Contains(text, q.text):
+		score = scoreContainName
+	}
+
+	words := make(map[string]struct{}, len(nameTokens))
+	for _, token := range nameTokens {
+		words[token] = struct{}{}
+	}
+	matched := 0
+	for _, token := range q.tokens {
+		if _, ok := words[token]; ok {
+			matched++
+		}
+	}
+	return score + scoreTokens*float64(matched)/float64(len(q.tokens))
+}
+
+// rankMedias сортирует медиа по убыванию релевантности и возвращает оценки в том же порядке.
+// sources - источник каждого медиа по kinopoisk_id. При равной оценке сохраняется исходный порядок
+func rankMedias(name string, medias []*media.Media, sources map[int64]media.MediaSource) []float64 {
+	q := newRelevanceQuery(name)
+	scores := make(map[*media.Media]float64, len(medias))
+	for _, m := range medias {
+		scores[m] = q.score(m, sources[m.KinopoiskId])
+	}
+
+	sort.SliceStable(medias, func(i, j int) bool {
+		return scores[medias[i]] > scores[medias[j]]
+	})
+
+	result := make([]float64, len(medias))
+	for i, m := range medias {
+		result[i] = scores[m]
+	}
+	return result
+}
+
+// tokenize приводит строку к нижнему регистру, заменяет ё на е и разбивает на слова
+func tokenize(s string) []string {
+	s = strings.ReplaceAll(strings.ToLower(s), "ё", "е")
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// parseYear возвращает год, если token похож на год выпуска, иначе 0
+func parseYear(token string) int {
+	if len(token) != 4 {
+		return 0
+	}
+	year, err := strconv.Atoi(token)
+	if err != nil || year < 1880 || year > 2100 {
+		return 0
+	}
+	return year
+}
+
+// yearDistance возвращает разницу в годах между годом медиа и year.
+// Для сериалов с годом в формате YYYY-YYYY год внутри периода дает 0. -1 - год медиа неизвестен
+func yearDistance(mediaYear string, year int) int {
+	from, to, _ := strings.Cut(mediaYear, "-")
+	start := parseYear(strings.TrimSpace(from))
+	if start == 0 {
+		return -1
+	}
+	end := parseYear(strings.TrimSpace(to))
+	if end == 0 {
+		end = start
+	}
+
+	switch {
+	case year < start:
+		return start - year
+	case year > end:
+		return year - end
+	default:
+		return 0
+	}
+}
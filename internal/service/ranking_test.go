@@ -0,0 +1,167 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/watchlist-kata/protos/media"
+)
+
+func TestNewRelevanceQuery(t *testing.T) {
+	tests := []struct {
+		name string
+		want relevanceQuery
+	}{
+		{name: "Матрица", want: relevanceQuery{text: "матрица", tokens: []string{"матрица"}}},
+		{name: "  Ёлки  2 ", want: relevanceQuery{text: "елки 2", tokens: []string{"елки", "2"}}},
+		{name: "1984", want: relevanceQuery{text: "1984", tokens: []string{"1984"}}},
+		{name: "Матрица 1999", want: relevanceQuery{text: "матрица", tokens: []string{"матрица"}, year: 1999}},
+		{name: "1917 2019", want: relevanceQuery{text: "2019", tokens: []string{"2019"}, year: 1917}},
+		{name: "Дюна 3000", want: relevanceQuery{text: "дюна 3000", tokens: []string{"дюна", "3000"}}},
+		{name: "", want: relevanceQuery{text: "", tokens: []string{}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newRelevanceQuery(tt.name); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("newRelevanceQuery(%q) = %+v, want %+v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestYearDistance(t *testing.T) {
+	tests := []struct {
+		mediaYear string
+		year      int
+		want      int
+	}{
+		{"1999", 1999, 0},
+		{"1999", 2003, 4},
+		{"2003", 1999, 4},
+		{"2010-2015", 2012, 0},
+		{"2010-2015", 2010, 0},
+		{"2010-2015", 2017, 2},
+		{"2010-2015", 2008, 2},
+		{"2010-", 2012, 2},
+		{"", 2012, -1},
+		{"unknown", 2012, -1},
+	}
+
+	for _, tt := range tests {
+		if got := yearDistance(tt.mediaYear, tt.year); got != tt.want {
+			t.Errorf("yearDistance(%q, %d) = %d, want %d", tt.mediaYear, tt.year, got, tt.want)
+		}
+	}
+}
+
+func TestScore(t *testing.T) {
+	kinopoisk := media.MediaSource_MEDIA_SOURCE_KINOPOISK
+	local := media.MediaSource_MEDIA_SOURCE_LOCAL
+
+	tests := []struct {
+		name   string
+		query  string
+		media  *media.Media
+		source media.MediaSource
+		want   float64
+	}{
+		{name: "exact", query: "Матрица", media: &media.Media{NameRu: "Матрица"}, source: kinopoisk, want: scoreExactName + scoreTokens},
+		{name: "exact ignores case and ё", query: "ЕЛКИ", media: &media.Media{NameRu: "Ёлки"}, source: kinopoisk, want: scoreExactName + scoreTokens},
+		{name: "prefix", query: "Матрица", media: &media.Media{NameRu: "Матрица: Перезагрузка"}, source: kinopoisk, want: scorePrefixName + scoreTokens},
+		{name: "contains", query: "перезагрузка", media: &media.Media{NameRu: "Матрица: Перезагрузка"}, source: kinopoisk, want: scoreContainName + scoreTokens},
+		{name: "half of tokens", query: "матрица революция", media: &media.Media{NameRu: "Матрица"}, source: kinopoisk, want: scoreTokens / 2},
+		{name: "best of two names", query: "The Matrix", media: &media.Media{NameRu: "Матрица", NameEn: "The Matrix"}, source: kinopoisk, want: scoreExactName + scoreTokens},
+		{name: "no match", query: "Титаник", media: &media.Media{NameRu: "Матрица"}, source: kinopoisk, want: 0},
+		{name: "local bonus", query: "Матрица", media: &media.Media{NameRu: "Матрица"}, source: local, want: scoreExactName + scoreTokens + scoreLocal},
+		{name: "year match", query: "Матрица 1999", media: &media.Media{NameRu: "Матрица", Year: "1999"}, source: kinopoisk, want: scoreExactName + scoreTokens + scoreYear},
+		{name: "year off by two", query: "Матрица 1999", media: &media.Media{NameRu: "Матрица", Year: "2001"}, source: kinopoisk, want: scoreExactName + scoreTokens + scoreYear - 2*scoreYearStep},
+		{name: "year too far", query: "Матрица 1999", media: &media.Media{NameRu: "Матрица", Year: "2021"}, source: kinopoisk, want: scoreExactName + scoreTokens},
+		{name: "unknown year", query: "Матрица 1999", media: &media.Media{NameRu: "Матрица"}, source: kinopoisk, want: scoreExactName + scoreTokens},
+		{name: "empty name", query: "Матрица", media: &media.Media{}, source: kinopoisk, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newRelevanceQuery(tt.query).score(tt.media, tt.source); got != tt.want {
+				t.Errorf("score = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRankMedias(t *testing.T) {
+	kinopoisk := media.MediaSource_MEDIA_SOURCE_KINOPOISK
+	local := media.MediaSource_MEDIA_SOURCE_LOCAL
+
+	tests := []struct {
+		name    string
+		query   string
+		medias  []*media.Media
+		sources map[int64]media.MediaSource
+		want    []int64 // kinopoisk_id в ожидаемом порядке
+	}{
+		{
+			name:  "exact before prefix before contains",
+			query: "Матрица",
+			medias: []*media.Media{
+				{KinopoiskId: 1, NameRu: "Это не Матрица"},
+				{KinopoiskId: 2, NameRu: "Матрица: Перезагрузка"},
+				{KinopoiskId: 3, NameRu: "Матрица"},
+			},
+			want: []int64{3, 2, 1},
+		},
+		{
+			name:  "year breaks name tie",
+			query: "Дюна 2021",
+			medias: []*media.Media{
+				{KinopoiskId: 1, NameRu: "Дюна", Year: "1984"},
+				{KinopoiskId: 2, NameRu: "Дюна", Year: "2021"},
+			},
+			want: []int64{2, 1},
+		},
+		{
+			name:  "local wins a tie with Kinopoisk",
+			query: "Дюна",
+			medias: []*media.Media{
+				{KinopoiskId: 1, NameRu: "Дюна"},
+				{KinopoiskId: 2, NameRu: "Дюна"},
+			},
+			sources: map[int64]media.MediaSource{1: kinopoisk, 2: local},
+			want:    []int64{2, 1},
+		},
+		{
+			name:  "equal scores keep input order",
+			query: "Дюна",
+			medias: []*media.Media{
+				{KinopoiskId: 3, NameRu: "Дюна"},
+				{KinopoiskId: 1, NameRu: "Дюна"},
+				{KinopoiskId: 2, NameRu: "Дюна"},
+			},
+			want: []int64{3, 1, 2},
+		},
+		{name: "empty list", query: "Дюна", medias: []*media.Media{}, want: []int64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scores := rankMedias(tt.query, tt.medias, tt.sources)
+
+			got := make([]int64, 0, len(tt.medias))
+			for _, m := range tt.medias {
+				got = append(got, m.KinopoiskId)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("order = %v, want %v", got, tt.want)
+			}
+			if len(scores) != len(tt.medias) {
+				t.Fatalf("got %d scores for %d media", len(scores), len(tt.medias))
+			}
+			for i := 1; i < len(scores); i++ {
+				if scores[i] > scores[i-1] {
+					t.Errorf("scores are not sorted: %v", scores)
+				}
+			}
+		})
+	}
+}
@@ -6,24 +6,61 @@ import (
 	"fmt"
 	"log/slog"
 	"reflect"
+	"slices"
 	"sync"
 	"time"
 
+	"github.com/watchlist-kata/media/internal/audit"
 	"github.com/watchlist-kata/media/internal/config"
 	"github.com/watchlist-kata/media/internal/kinopoisk"
 	"github.com/watchlist-kata/media/internal/repository"
+	"github.com/watchlist-kata/media/internal/validation"
 	"github.com/watchlist-kata/protos/media"
-	"gorm.io/gorm"
+	"google.golang.org/protobuf/proto"
 )
 
+// kinopoiskDBReserve - часть оставшегося времени запроса, которая
+// резервируется для сохранения результатов Кинопоиска в базу данных
+const kinopoiskDBReserve = 500 * time.Millisecond
+
+// Размер страницы ListMedia
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// sortFields сопоставляет поле сортировки из запроса с полем репозитория
+var sortFields = map[media.MediaSortField]repository.SortField{
+	media.MediaSortField_MEDIA_SORT_FIELD_UNSPECIFIED: repository.SortByID,
+	media.MediaSortField_MEDIA_SORT_FIELD_NAME:        repository.SortByName,
+	media.MediaSortField_MEDIA_SORT_FIELD_YEAR:        repository.SortByYear,
+	media.MediaSortField_MEDIA_SORT_FIELD_CREATED_AT:  repository.SortByCreatedAt,
+	media.MediaSortField_MEDIA_SORT_FIELD_UPDATED_AT:  repository.SortByUpdatedAt,
+}
+
 // Service определяет интерфейс для сервиса
 type Service interface {
 	GetMediaByID(ctx context.Context, req *media.GetMediaByIDRequest) (*media.Media, error)
 	GetMediasByName(ctx context.Context, req *media.GetMediasByNameRequest) (*media.MediaList, error)
 	SearchKinopoisk(ctx context.Context, name string) ([]*media.Media, error)
 	SaveMedia(ctx context.Context, req *media.SaveMediaRequest) (*media.Media, error)
-	UpdateMedia(ctx context.Context, m *media.Media) (*media.Media, error)
+	UpdateMedia(ctx context.Context, req *media.SaveMediaRequest) (*media.Media, error)
 	DeleteMedia(ctx context.Context, req *media.DeleteMediaRequest) (*media.DeleteMediaResponse, error)
+	ListMedia(ctx context.Context, req *media.ListMediaRequest) (*media.ListMediaResponse, error)
+	RestoreMedia(ctx context.Context, req *media.RestoreMediaRequest) (*media.Media, error)
+	PurgeMedia(ctx context.Context, req *media.PurgeMediaRequest) (*media.DeleteMediaResponse, error)
+	GetMediaHistory(ctx context.Context, req *media.GetMediaHistoryRequest) (*media.GetMediaHistoryResponse, error)
+	ImportMedia(ctx context.Context, kinopoiskID int64) (*media.Media, error)
+	RefreshMedia(ctx context.Context, kinopoiskID int64) (*media.Media, error)
+	WatchMediaChanges(ctx context.Context, req *media.WatchMediaChangesRequest, send func(*media.MediaHistoryEntry) error) error
+	SearchMediaStream(ctx context.Context, req *media.GetMediasByNameRequest, send func(*media.SearchMediaStreamResponse) error) error
+	ExportMedia(ctx context.Context, req *media.ExportMediaRequest, send func(*media.Media) error) error
+}
+
+// kinopoiskAPI - методы клиента Кинопоиска, которые использует сервис
+type kinopoiskAPI interface {
+	SearchByKeyword(ctx context.Context, keyword string) ([]*media.Media, error)
+	GetByID(ctx context.Context, kinopoiskID int64) (*media.Media, error)
 }
 
 // MediaService представляет собой структуру сервиса
@@ -31,28 +68,57 @@ type MediaService struct {
 	repo            repository.Repository
 	logger          *slog.Logger
 	cfg             *config.Config
-	kinopoiskClient *kinopoisk.KPClient
+	kinopoiskClient kinopoiskAPI
 	wg              sync.WaitGroup
 	retryCount      int
 	retryInterval   time.Duration
+	searchMode      repository.SearchMode
+	changes         *ChangeBroadcaster
+	freshnessTTL    time.Duration
+	limiter         *kinopoisk.Limiter // Общий лимит фоновых запросов к Кинопоиску
+	refreshQueue    chan refreshTask   // Очередь фонового обновления устаревших медиа
+	refreshMu       sync.Mutex
+	refreshing      map[int64]struct{} // Медиа в очереди или в процессе обновления
+	refreshCtx      context.Context    // Отменяется при остановке сервиса
+	stopRefresh     context.CancelFunc
 }
 
-// NewMediaService создает новый экземпляр MediaService
-func NewMediaService(repo repository.Repository, logger *slog.Logger, cfg *config.Config) (Service, error) {
+// NewMediaService создает новый экземпляр MediaService.
+// limiter ограничивает фоновое обновление устаревших медиа и может быть общим с другими фоновыми задачами
+func NewMediaService(repo repository.Repository, logger *slog.Logger, cfg *config.Config, limiter *kinopoisk.Limiter) (Service, error) {
 	kinopoiskAPIKey := cfg.KinopoiskAPIKey
 	kinopoiskClient, err := kinopoisk.NewKinopoiskClient(kinopoiskAPIKey, logger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize Kinopoisk client: %w", err)
 	}
 
-	return &MediaService{
+	searchMode, err := repository.ParseSearchMode(cfg.LocalSearchMode)
+	if err != nil {
+		return nil, fmt.Errorf("invalid local search mode: %w", err)
+	}
+
+	refreshCtx, stopRefresh := context.WithCancel(context.Background())
+
+	s := &MediaService{
 		repo:            repo,
 		logger:          logger,
 		cfg:             cfg,
 		kinopoiskClient: kinopoiskClient,
 		retryCount:      3,
 		retryInterval:   2 * time.Second,
-	}, nil
+		searchMode:      searchMode,
+		changes:         NewChangeBroadcaster(),
+		freshnessTTL:    cfg.FreshnessTTL,
+		limiter:         limiter,
+		refreshQueue:    make(chan refreshTask, refreshQueueSize),
+		refreshing:      make(map[int64]struct{}),
+		refreshCtx:      refreshCtx,
+		stopRefresh:     stopRefresh,
+	}
+	if s.freshnessTTL > 0 {
+		s.startRefreshWorkers()
+	}
+	return s, nil
 }
 
 // Verify that MediaService implements the Service interface at compile time.
@@ -81,11 +147,41 @@ func (s *MediaService) GetMediaByID(ctx context.Context, req *media.GetMediaByID
 		return nil, s.handleError(ctx, "Failed to GetMediaByID", fmt.Errorf("failed to get media with id %d: %w", req.Id, err), "id", req.Id, "error", err)
 	}
 
+	// Устаревшие данные отдаем сразу, а обновляем в фоне
+	if s.stale(m) {
+		s.scheduleRefresh(ctx, m)
+	}
+
 	s.logger.InfoContext(ctx, "GetMediaByID successful", "id", req.Id)
 	return m, nil
 }
 
-// GetMediasByName получает медиа по названию
+// searchPlan - источники поиска по названию, выбранные режимом запроса
+type searchPlan struct {
+	local   bool // Искать в локальной базе
+	remote  bool // Искать в Кинопоиске
+	persist bool // Сохранять результаты Кинопоиска в базу
+}
+
+// newSearchPlan разбирает режим поиска из запроса. Режим не задан - HYBRID
+func newSearchPlan(req *media.GetMediasByNameRequest) (searchPlan, error) {
+	plan := searchPlan{persist: !req.SkipPersistence}
+	switch req.Mode {
+	case media.MediaSearchMode_MEDIA_SEARCH_MODE_UNSPECIFIED, media.MediaSearchMode_MEDIA_SEARCH_MODE_HYBRID:
+		plan.local, plan.remote = true, true
+	case media.MediaSearchMode_MEDIA_SEARCH_MODE_LOCAL:
+		plan.local = true
+	case media.MediaSearchMode_MEDIA_SEARCH_MODE_REMOTE:
+		plan.remote = true
+	default:
+		verr := &validation.Error{}
+		verr.Add("mode", "unknown search mode")
+		return plan, verr.Err()
+	}
+	return plan, nil
+}
+
+// GetMediasByName получает медиа по названию из локальной базы и/или Кинопоиска в зависимости от req.Mode
 func (s *MediaService) GetMediasByName(ctx context.Context, req *media.GetMediasByNameRequest) (*media.MediaList, error) {
 	if req == nil {
 		return nil, fmt.Errorf("invalid request: nil pointer")
@@ -95,7 +191,12 @@ func (s *MediaService) GetMediasByName(ctx context.Context, req *media.GetMedias
 		return nil, fmt.Errorf("invalid name: cannot be empty")
 	}
 
-	s.logger.InfoContext(ctx, "GetMediasByName called", "name", req.Name)
+	s.logger.InfoContext(ctx, "GetMediasByName called", "name", req.Name, "mode", req.Mode, "skip_persistence", req.SkipPersistence)
+
+	plan, err := newSearchPlan(req)
+	if err != nil {
+		return nil, err
+	}
 
 	select {
 	case <-ctx.Done():
@@ -103,111 +204,178 @@ func (s *MediaService) GetMediasByName(ctx context.Context, req *media.GetMedias
 	default:
 	}
 
-	// 1. Поиск медиа в Кинопоиске
-	kinopoiskMedias, err := s.SearchKinopoisk(ctx, req.Name)
-	if err != nil {
-		s.logger.ErrorContext(ctx, "Failed to search Kinopoisk", "name", req.Name, "error", err)
-		return nil, s.handleError(ctx, "Failed to search Kinopoisk", fmt.Errorf("failed to search Kinopoisk: %w", err), "name", req.Name, "error", err)
+	// 1. Поиск медиа в Кинопоиске, отдаем ему только оставшееся время запроса
+	var kinopoiskMedias []*media.Media
+	if plan.remote {
+		kpCtx, kpCancel := kinopoiskContext(ctx)
+		kinopoiskMedias, err = s.SearchKinopoisk(kpCtx, req.Name)
+		kpCancel()
+		if err != nil {
+			s.logger.ErrorContext(ctx, "Failed to search Kinopoisk", "name", req.Name, "error", err)
+			return nil, s.handleError(ctx, "Failed to search Kinopoisk", fmt.Errorf("failed to search Kinopoisk: %w", err), "name", req.Name, "error", err)
+		}
 	}
 
 	// 2. Получение медиа из локальной базы данных
-	localMedias, err := s.repo.GetMediasByNameFromRepo(ctx, req.Name)
-	if err != nil {
-		return nil, s.handleError(ctx, "Failed to GetMediasByName from DB", fmt.Errorf("failed to get medias by name %s from DB: %w", req.Name, err), "name", req.Name, "error", err)
+	var localMedias []*media.Media
+	if plan.local {
+		localMedias, err = s.repo.GetMediasByNameFromRepo(ctx, req.Name, s.searchMode)
+		if err != nil {
+			return nil, s.handleError(ctx, "Failed to GetMediasByName from DB", fmt.Errorf("failed to get medias by name %s from DB: %w", req.Name, err), "name", req.Name, "error", err)
+		}
+
+		// Если точный поиск ничего не нашел, пробуем нечеткий поиск с учетом опечаток
+		if len(localMedias) == 0 && len(kinopoiskMedias) == 0 && s.searchMode != repository.SearchModeTrigram {
+			s.logger.InfoContext(ctx, "No exact matches, falling back to fuzzy search", "name", req.Name)
+			localMedias, err = s.repo.GetMediasByNameFromRepo(ctx, req.Name, repository.SearchModeTrigram)
+			if err != nil {
+				return nil, s.handleError(ctx, "Failed to fuzzy search media in DB", fmt.Errorf("failed to fuzzy search medias by name %s in DB: %w", req.Name, err), "name", req.Name, "error", err)
+			}
+		}
 	}
 
+	// Записи из Кинопоиска сохраняются от имени синхронизации, а не вызывающего
+	syncCtx := audit.WithActor(ctx, audit.ActorKinopoiskSync)
+
 	// 3. Объединение результатов
 	var mediaPointers []*media.Media
 	mediaMap := make(map[int64]*media.Media)
+	sources := make(map[int64]media.MediaSource)
 
 	// Сначала добавляем локальные медиа
 	for _, m := range localMedias {
 		if _, exists := mediaMap[m.KinopoiskId]; !exists {
 			mediaPointers = append(mediaPointers, m)
 			mediaMap[m.KinopoiskId] = m
+			sources[m.KinopoiskId] = media.MediaSource_MEDIA_SOURCE_LOCAL
 		}
 	}
 
 	// Сохраняем/обновляем и добавляем медиа из Кинопоиска
 	for _, kpMedia := range kinopoiskMedias {
-		// Проверяем, есть ли уже такое медиа в результатах
-		if existingMedia, exists := mediaMap[kpMedia.KinopoiskId]; !exists {
-			// Если нет в результатах - проверяем в БД
-			dbMedia, err := s.repo.GetMediaByKinopoiskID(ctx, kpMedia.KinopoiskId)
-			if err != nil {
-				if errors.Is(err, repository.ErrMediaNotFound) {
-					// Медиа нет в базе - сохраняем его
-					s.logger.InfoContext(ctx, "Saving media from Kinopoisk", "kinopoiskID", kpMedia.KinopoiskId)
-					// Заполняем поля времени перед сохранением
-					kpMedia.CreatedAt = time.Now().Format(time.RFC3339)
-					kpMedia.UpdatedAt = time.Now().Format(time.RFC3339)
-					savedMedia, saveErr := s.repo.CreateMedia(ctx, kpMedia)
-					if saveErr != nil {
-						s.logger.ErrorContext(ctx, "Failed to save media from Kinopoisk", "kinopoiskID", kpMedia.KinopoiskId, "error", saveErr)
-						mediaPointers = append(mediaPointers, kpMedia) // Даже если не удалось сохранить, добавляем в результаты
-					} else {
-						s.logger.InfoContext(ctx, "Media from Kinopoisk saved successfully", "kinopoiskID", kpMedia.KinopoiskId)
-						mediaPointers = append(mediaPointers, savedMedia)
-					}
-				} else {
-					s.logger.ErrorContext(ctx, "Failed to check existing media in DB", "kinopoiskID", kpMedia.KinopoiskId, "error", err)
-					mediaPointers = append(mediaPointers, kpMedia) // Добавляем несмотря на ошибку
-				}
-				mediaMap[kpMedia.KinopoiskId] = kpMedia
-			} else {
-				// Медиа есть в БД, но не в текущих результатах
-				if needsUpdate(dbMedia, kpMedia) {
-					s.logger.InfoContext(ctx, "Updating media from Kinopoisk", "kinopoiskID", kpMedia.KinopoiskId)
-					updatedMedia, updateErr := s.repo.UpdateMedia(ctx, kpMedia)
-					if updateErr != nil {
-						s.logger.ErrorContext(ctx, "Failed to update media from Kinopoisk", "kinopoiskID", kpMedia.KinopoiskId, "error", updateErr)
-						mediaPointers = append(mediaPointers, dbMedia)
-					} else {
-						s.logger.InfoContext(ctx, "Media updated successfully", "kinopoiskID", kpMedia.KinopoiskId)
-						mediaPointers = append(mediaPointers, updatedMedia)
-					}
-				} else {
-					// Обновление не требуется, используем версию из БД
-					mediaPointers = append(mediaPointers, dbMedia)
-				}
+		existingMedia, exists := mediaMap[kpMedia.KinopoiskId]
+		if !plan.persist {
+			// Без сохранения отдаем данные Кинопоиска как есть, локальные записи не трогаем
+			if !exists {
+				mediaPointers = append(mediaPointers, kpMedia)
 				mediaMap[kpMedia.KinopoiskId] = kpMedia
+				sources[kpMedia.KinopoiskId] = media.MediaSource_MEDIA_SOURCE_KINOPOISK
 			}
-		} else {
-			// Если медиа уже есть в результатах, проверяем, нужно ли обновить
-			if needsUpdate(existingMedia, kpMedia) {
-				// Обновляем существующее медиа данными из Кинопоиска
-				s.logger.InfoContext(ctx, "Updating media with Kinopoisk data", "kinopoiskID", kpMedia.KinopoiskId)
-
-				// Если у медиа есть ID в базе, обновляем через репозиторий
-				if existingMedia.Id > 0 {
-					kpMedia.Id = existingMedia.Id // Сохраняем ID из БД
-					updatedMedia, updateErr := s.repo.UpdateMedia(ctx, kpMedia)
-					if updateErr != nil {
-						s.logger.ErrorContext(ctx, "Failed to update existing media", "kinopoiskID", kpMedia.KinopoiskId, "error", updateErr)
-					} else {
-						// Заменяем медиа в результатах
-						for i, m := range mediaPointers {
-							if m.KinopoiskId == updatedMedia.KinopoiskId {
-								mediaPointers[i] = updatedMedia
-								break
-							}
-						}
-						mediaMap[kpMedia.KinopoiskId] = updatedMedia
-					}
+			continue
+		}
+		if !exists {
+			// Если нет в результатах - сохраняем или обновляем запись в БД
+			m, _ := s.syncKinopoiskMedia(ctx, syncCtx, kpMedia, nil)
+			mediaPointers = append(mediaPointers, m)
+			mediaMap[kpMedia.KinopoiskId] = m
+			sources[kpMedia.KinopoiskId] = media.MediaSource_MEDIA_SOURCE_KINOPOISK
+		} else if updatedMedia, changed := s.syncKinopoiskMedia(ctx, syncCtx, kpMedia, existingMedia); changed {
+			// Заменяем медиа в результатах
+			for i, m := range mediaPointers {
+				if m.KinopoiskId == updatedMedia.KinopoiskId {
+					mediaPointers[i] = updatedMedia
+					break
 				}
 			}
+			mediaMap[kpMedia.KinopoiskId] = updatedMedia
 		}
 	}
 
+	// 4. Сортировка по релевантности
+	scores := rankMedias(req.Name, mediaPointers, sources)
+
 	// Формируем итоговый ответ
 	result := &media.MediaList{
 		Medias: mediaPointers,
+		Scores: scores,
 	}
 
 	s.logger.InfoContext(ctx, "GetMediasByName successful", "totalMedias", len(result.Medias))
 	return result, nil
 }
 
+// syncKinopoiskMedia сохраняет или обновляет медиа из Кинопоиска и возвращает запись для результатов поиска.
+// local - медиа с тем же kinopoisk_id, уже попавшее в результаты, или nil.
+// changed сообщает, что результат отличается от local
+func (s *MediaService) syncKinopoiskMedia(ctx, syncCtx context.Context, kpMedia, local *media.Media) (result *media.Media, changed bool) {
+	if local != nil {
+		// Медиа уже есть в результатах, проверяем, нужно ли обновить. Свежие записи не трогаем
+		if s.fresh(local) || local.Id <= 0 {
+			return local, false
+		}
+		if !needsUpdate(local, kpMedia) {
+			if s.stale(local) {
+				s.touchMedia(syncCtx, local)
+			}
+			return local, false
+		}
+		if validation.ValidateMedia(kpMedia) != nil {
+			return local, false
+		}
+		s.logger.InfoContext(ctx, "Updating media with Kinopoisk data", "kinopoiskID", kpMedia.KinopoiskId)
+		kpMedia.Id = local.Id // Сохраняем ID и версию из БД
+		kpMedia.Version = local.Version
+		updatedMedia, updateErr := s.repo.UpdateMedia(syncCtx, kpMedia, nil)
+		if updateErr != nil {
+			s.logger.ErrorContext(ctx, "Failed to update existing media", "kinopoiskID", kpMedia.KinopoiskId, "error", updateErr)
+			return local, false
+		}
+		s.publishChange(updatedMedia)
+		return updatedMedia, true
+	}
+
+	// Если нет в результатах - проверяем в БД
+	dbMedia, err := s.repo.GetMediaByKinopoiskID(ctx, kpMedia.KinopoiskId)
+	if err != nil {
+		if validateErr := validation.ValidateMedia(kpMedia); validateErr != nil {
+			// Невалидные данные Кинопоиска не сохраняем, но возвращаем в результатах
+			s.logger.WarnContext(ctx, "Skipping invalid media from Kinopoisk", "kinopoiskID", kpMedia.KinopoiskId, "error", validateErr)
+			return kpMedia, true
+		}
+		if !errors.Is(err, repository.ErrMediaNotFound) {
+			s.logger.ErrorContext(ctx, "Failed to check existing media in DB", "kinopoiskID", kpMedia.KinopoiskId, "error", err)
+			return kpMedia, true // Добавляем несмотря на ошибку
+		}
+
+		// Медиа нет в базе - сохраняем его
+		s.logger.InfoContext(ctx, "Saving media from Kinopoisk", "kinopoiskID", kpMedia.KinopoiskId)
+		// Заполняем поля времени перед сохранением
+		kpMedia.CreatedAt = time.Now().Format(time.RFC3339)
+		kpMedia.UpdatedAt = time.Now().Format(time.RFC3339)
+		savedMedia, saveErr := s.repo.CreateMedia(syncCtx, kpMedia)
+		if saveErr != nil {
+			s.logger.ErrorContext(ctx, "Failed to save media from Kinopoisk", "kinopoiskID", kpMedia.KinopoiskId, "error", saveErr)
+			return kpMedia, true // Даже если не удалось сохранить, добавляем в результаты
+		}
+		s.logger.InfoContext(ctx, "Media from Kinopoisk saved successfully", "kinopoiskID", kpMedia.KinopoiskId)
+		s.publishChange(savedMedia)
+		return savedMedia, true
+	}
+
+	// Медиа есть в БД, но не в текущих результатах
+	if s.fresh(dbMedia) || validation.ValidateMedia(kpMedia) != nil {
+		// Обновление не требуется, используем версию из БД
+		return dbMedia, true
+	}
+	if !needsUpdate(dbMedia, kpMedia) {
+		if s.stale(dbMedia) {
+			s.touchMedia(syncCtx, dbMedia)
+		}
+		return dbMedia, true
+	}
+	s.logger.InfoContext(ctx, "Updating media from Kinopoisk", "kinopoiskID", kpMedia.KinopoiskId)
+	kpMedia.Id = dbMedia.Id
+	kpMedia.Version = dbMedia.Version
+	updatedMedia, updateErr := s.repo.UpdateMedia(syncCtx, kpMedia, nil)
+	if updateErr != nil {
+		s.logger.ErrorContext(ctx, "Failed to update media from Kinopoisk", "kinopoiskID", kpMedia.KinopoiskId, "error", updateErr)
+		return dbMedia, true
+	}
+	s.logger.InfoContext(ctx, "Media updated successfully", "kinopoiskID", kpMedia.KinopoiskId)
+	s.publishChange(updatedMedia)
+	return updatedMedia, true
+}
+
 // SearchKinopoisk ищет медиа в Кинопоиске.
 func (s *MediaService) SearchKinopoisk(ctx context.Context, name string) ([]*media.Media, error) {
 	s.logger.InfoContext(ctx, "SearchKinopoisk called", "name", name)
@@ -227,15 +395,15 @@ func (s *MediaService) SearchKinopoisk(ctx context.Context, name string) ([]*med
 
 // SaveMedia сохраняет новое медиа
 func (s *MediaService) SaveMedia(ctx context.Context, req *media.SaveMediaRequest) (*media.Media, error) {
-	s.logger.InfoContext(ctx, "SaveMedia called", "kinopoiskID", req.Media.KinopoiskId)
-
-	// Базовая валидация входных данных
-	if req.Media == nil {
+	if req == nil || req.Media == nil {
 		return nil, fmt.Errorf("invalid request: nil media")
 	}
 
-	if req.Media.KinopoiskId <= 0 {
-		return nil, fmt.Errorf("invalid kinopoiskID: must be greater than 0")
+	s.logger.InfoContext(ctx, "SaveMedia called", "kinopoiskID", req.Media.KinopoiskId)
+
+	if err := validation.ValidateMedia(req.Media); err != nil {
+		s.logger.WarnContext(ctx, "SaveMedia validation failed", "kinopoiskID", req.Media.KinopoiskId, "error", err)
+		return nil, err
 	}
 
 	newMedia, err := s.repo.CreateMedia(ctx, req.Media)
@@ -243,27 +411,56 @@ func (s *MediaService) SaveMedia(ctx context.Context, req *media.SaveMediaReques
 		s.logger.ErrorContext(ctx, "Failed to SaveMedia", "kinopoiskID", req.Media.KinopoiskId, "error", err)
 		return nil, s.handleError(ctx, "Failed to SaveMedia", fmt.Errorf("failed to save media with kinopoisk_id %d: %w", req.Media.KinopoiskId, err), "kinopoiskID", req.Media.KinopoiskId)
 	}
+	s.publishChange(newMedia)
 	return newMedia, nil
 }
 
-// UpdateMedia обновляет существующее медиа
-func (s *MediaService) UpdateMedia(ctx context.Context, m *media.Media) (*media.Media, error) {
-	s.logger.InfoContext(ctx, "UpdateMedia called", "kinopoiskID", m.KinopoiskId)
+// UpdateMedia обновляет существующее медиа.
+// Если задан update_mask, обновляются только перечисленные поля
+func (s *MediaService) UpdateMedia(ctx context.Context, req *media.SaveMediaRequest) (*media.Media, error) {
+	if req == nil || req.Media == nil {
+		return nil, fmt.Errorf("invalid request: nil media")
+	}
+	m := req.Media
+	paths := req.GetUpdateMask().GetPaths()
 
-	// Базовая валидация входных данных
+	s.logger.InfoContext(ctx, "UpdateMedia called", "kinopoiskID", m.KinopoiskId, "update_mask", paths)
+
+	if err := validation.ValidateUpdateMask(paths); err != nil {
+		s.logger.WarnContext(ctx, "UpdateMedia invalid update mask", "mediaID", m.Id, "error", err)
+		return nil, err
+	}
 	if m.Id <= 0 {
-		return nil, fmt.Errorf("invalid media ID: must be greater than 0")
+		verr := &validation.Error{}
+		verr.Add("media.id", "must be greater than 0")
+		return nil, verr
 	}
 
 	existingMedia, err := s.repo.GetMediaByID(ctx, m.Id)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
+		if errors.Is(err, repository.ErrMediaNotFound) {
 			s.logger.WarnContext(ctx, "Media with id not found", "mediaID", m.Id, "error", err)
 			return nil, fmt.Errorf("media with id %d not found: %w", m.Id, err)
 		}
 		return nil, s.handleError(ctx, "Failed to UpdateMedia", fmt.Errorf("failed to get existing media with id %d: %w", m.Id, err), "mediaID", m.Id, "error", err)
 	}
 
+	// При частичном обновлении накладываем выбранные поля на текущую запись
+	if len(paths) > 0 {
+		m = applyUpdateMask(existingMedia, m, paths)
+	}
+
+	if err := validation.ValidateMediaUpdate(m, paths); err != nil {
+		s.logger.WarnContext(ctx, "UpdateMedia validation failed", "mediaID", m.Id, "error", err)
+		return nil, err
+	}
+
+	// Устаревшую версию отклоняем, даже если изменений нет
+	if m.Version != existingMedia.Version {
+		s.logger.WarnContext(ctx, "UpdateMedia stale version", "mediaID", m.Id, "request_version", m.Version, "db_version", existingMedia.Version)
+		return nil, fmt.Errorf("%w: media with id %d is at version %d, got %d", repository.ErrVersionConflict, m.Id, existingMedia.Version, m.Version)
+	}
+
 	// Сравниваем поля и определяем, нужно ли обновлять запись
 	if !needsUpdate(existingMedia, m) {
 		s.logger.InfoContext(ctx, "No fields to update", "mediaID", m.Id)
@@ -271,36 +468,228 @@ func (s *MediaService) UpdateMedia(ctx context.Context, m *media.Media) (*media.
 	}
 
 	s.logger.InfoContext(ctx, "Updating media", "mediaID", m.Id)
-	updatedMedia, err := s.repo.UpdateMedia(ctx, m)
+	updatedMedia, err := s.repo.UpdateMedia(ctx, m, paths)
 	if err != nil {
 		return nil, s.handleError(ctx, "Failed to UpdateMedia in repository", fmt.Errorf("failed to update media with id %d in repository: %w", m.Id, err), "mediaID", m.Id, "error", err)
 	}
 
 	s.logger.InfoContext(ctx, "Media updated successfully", "mediaID", m.Id)
+	s.publishChange(updatedMedia)
 	return updatedMedia, nil
 }
 
+// applyUpdateMask возвращает копию existing с полями из paths, взятыми из update
+func applyUpdateMask(existing, update *media.Media, paths []string) *media.Media {
+	merged := proto.Clone(existing).(*media.Media)
+	merged.Version = update.Version
+	for _, path := range paths {
+		switch path {
+		case "type":
+			merged.Type = update.Type
+		case "name_en":
+			merged.NameEn = update.NameEn
+		case "name_ru":
+			merged.NameRu = update.NameRu
+		case "description":
+			merged.Description = update.Description
+		case "year":
+			merged.Year = update.Year
+		case "poster":
+			merged.Poster = update.Poster
+		case "countries":
+			// Список будет получен из строки при записи
+			merged.Countries, merged.CountryList = update.Countries, nil
+		case "genres":
+			merged.Genres, merged.GenreList = update.Genres, nil
+		case "country_list":
+			merged.CountryList, merged.Countries = update.CountryList, ""
+		case "genre_list":
+			merged.GenreList, merged.Genres = update.GenreList, ""
+		}
+	}
+	return merged
+}
+
 // DeleteMedia удаляет медиа по его ID
 func (s *MediaService) DeleteMedia(ctx context.Context, req *media.DeleteMediaRequest) (*media.DeleteMediaResponse, error) {
-	s.logger.InfoContext(ctx, "DeleteMedia called", "id", req.Id)
+	s.logger.InfoContext(ctx, "DeleteMedia called", "id", req.Id, "version", req.Version)
 
 	// Базовая валидация входных данных
+	verr := &validation.Error{}
 	if req.Id <= 0 {
-		return nil, fmt.Errorf("invalid media ID: must be greater than 0")
+		verr.Add("id", "must be greater than 0")
+	}
+	if req.Version <= 0 {
+		verr.Add("version", "must be greater than 0")
+	}
+	if err := verr.Err(); err != nil {
+		return nil, err
 	}
 
-	resp, err := s.repo.DeleteMedia(ctx, req.Id)
+	resp, err := s.repo.DeleteMedia(ctx, req.Id, req.Version)
 	if err != nil {
-		// Проверяем, является ли это ошибкой "запись не найдена"
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, fmt.Errorf("media with id %d not found", req.Id)
+		if errors.Is(err, repository.ErrMediaNotFound) {
+			s.logger.WarnContext(ctx, "Media with id not found", "mediaID", req.Id, "error", err)
+			return nil, fmt.Errorf("media with id %d not found: %w", req.Id, err)
 		}
 		return nil, s.handleError(ctx, "Failed to DeleteMedia", fmt.Errorf("failed to delete media with id %d: %w", req.Id, err), "id", req.Id, "error", err)
 	}
 
+	s.changes.Publish(req.Id)
+	return resp, nil
+}
+
+// RestoreMedia восстанавливает мягко удаленное медиа
+func (s *MediaService) RestoreMedia(ctx context.Context, req *media.RestoreMediaRequest) (*media.Media, error) {
+	if req == nil {
+		return nil, fmt.Errorf("invalid request: nil pointer")
+	}
+
+	s.logger.InfoContext(ctx, "RestoreMedia called", "id", req.Id)
+
+	if req.Id <= 0 {
+		verr := &validation.Error{}
+		verr.Add("id", "must be greater than 0")
+		return nil, verr
+	}
+
+	m, err := s.repo.RestoreMedia(ctx, req.Id)
+	if err != nil {
+		return nil, s.handleError(ctx, "Failed to RestoreMedia", fmt.Errorf("failed to restore media with id %d: %w", req.Id, err), "id", req.Id, "error", err)
+	}
+	s.publishChange(m)
+	return m, nil
+}
+
+// PurgeMedia окончательно удаляет медиа. Проверка прав выполняется на уровне API
+func (s *MediaService) PurgeMedia(ctx context.Context, req *media.PurgeMediaRequest) (*media.DeleteMediaResponse, error) {
+	if req == nil {
+		return nil, fmt.Errorf("invalid request: nil pointer")
+	}
+
+	s.logger.InfoContext(ctx, "PurgeMedia called", "id", req.Id)
+
+	if req.Id <= 0 {
+		verr := &validation.Error{}
+		verr.Add("id", "must be greater than 0")
+		return nil, verr
+	}
+
+	resp, err := s.repo.PurgeMedia(ctx, req.Id)
+	if err != nil {
+		return nil, s.handleError(ctx, "Failed to PurgeMedia", fmt.Errorf("failed to purge media with id %d: %w", req.Id, err), "id", req.Id, "error", err)
+	}
+	if resp.Success {
+		s.changes.Publish(req.Id)
+	}
 	return resp, nil
 }
 
+// ListMedia возвращает страницу медиа из локальной базы с фильтрами и сортировкой
+func (s *MediaService) ListMedia(ctx context.Context, req *media.ListMediaRequest) (*media.ListMediaResponse, error) {
+	if req == nil {
+		return nil, fmt.Errorf("invalid request: nil pointer")
+	}
+
+	s.logger.InfoContext(ctx, "ListMedia called", "page_size", req.PageSize, "sort_by", req.SortBy.String())
+
+	verr := &validation.Error{}
+	pageSize := int(req.PageSize)
+	switch {
+	case pageSize < 0:
+		verr.Add("page_size", "must not be negative")
+	case pageSize == 0:
+		pageSize = defaultPageSize
+	case pageSize > maxPageSize:
+		pageSize = maxPageSize
+	}
+	sortBy, ok := sortFields[req.SortBy]
+	if !ok {
+		verr.Add("sort_by", fmt.Sprintf("unknown sort field %d", req.SortBy))
+	}
+	if req.Type != "" {
+		if _, ok := validation.MediaTypes[req.Type]; !ok {
+			verr.Add("type", fmt.Sprintf("unknown type %q", req.Type))
+		}
+	}
+	if req.YearFrom < 0 {
+		verr.Add("year_from", "must not be negative")
+	}
+	if req.YearTo < 0 {
+		verr.Add("year_to", "must not be negative")
+	}
+	if req.YearFrom > 0 && req.YearTo > 0 && req.YearFrom > req.YearTo {
+		verr.Add("year_to", "must not be less than year_from")
+	}
+	if err := verr.Err(); err != nil {
+		return nil, err
+	}
+
+	medias, nextPageToken, err := s.repo.ListMedia(ctx, repository.ListMediaParams{
+		Limit:      pageSize,
+		Cursor:     req.PageToken,
+		SortBy:     sortBy,
+		Descending: req.Descending,
+		Type:       req.Type,
+		YearFrom:   int(req.YearFrom),
+		YearTo:     int(req.YearTo),
+		Genre:      req.Genre,
+		Country:    req.Country,
+	})
+	if err != nil {
+		return nil, s.handleError(ctx, "Failed to ListMedia", fmt.Errorf("failed to list media: %w", err), "error", err)
+	}
+
+	return &media.ListMediaResponse{Medias: medias, NextPageToken: nextPageToken}, nil
+}
+
+// GetMediaHistory возвращает страницу истории изменений медиа, начиная с новых записей
+func (s *MediaService) GetMediaHistory(ctx context.Context, req *media.GetMediaHistoryRequest) (*media.GetMediaHistoryResponse, error) {
+	if req == nil {
+		return nil, fmt.Errorf("invalid request: nil pointer")
+	}
+
+	s.logger.InfoContext(ctx, "GetMediaHistory called", "media_id", req.MediaId, "page_size", req.PageSize)
+
+	verr := &validation.Error{}
+	if req.MediaId <= 0 {
+		verr.Add("media_id", "must be greater than 0")
+	}
+	pageSize := int(req.PageSize)
+	switch {
+	case pageSize < 0:
+		verr.Add("page_size", "must not be negative")
+	case pageSize == 0:
+		pageSize = defaultPageSize
+	case pageSize > maxPageSize:
+		pageSize = maxPageSize
+	}
+	if err := verr.Err(); err != nil {
+		return nil, err
+	}
+
+	entries, nextPageToken, err := s.repo.GetMediaHistory(ctx, req.MediaId, pageSize, req.PageToken)
+	if err != nil {
+		return nil, s.handleError(ctx, "Failed to GetMediaHistory", fmt.Errorf("failed to get history of media with id %d: %w", req.MediaId, err), "media_id", req.MediaId, "error", err)
+	}
+
+	return &media.GetMediaHistoryResponse{Entries: entries, NextPageToken: nextPageToken}, nil
+}
+
+// kinopoiskContext ограничивает запрос в Кинопоиск оставшимся временем запроса
+// за вычетом резерва на работу с базой данных
+func kinopoiskContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return ctx, func() {}
+	}
+	remaining := time.Until(deadline)
+	if remaining <= kinopoiskDBReserve {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, remaining-kinopoiskDBReserve)
+}
+
 // handleError централизованно обрабатывает ошибки с логированием
 func (s *MediaService) handleError(ctx context.Context, message string, err error, args ...interface{}) error {
 	s.logger.ErrorContext(ctx, message, args...)
@@ -321,5 +710,7 @@ func needsUpdate(existingMedia, newMedia *media.Media) bool {
 		existingMedia.Year != newMedia.Year ||
 		existingMedia.Poster != newMedia.Poster ||
 		!reflect.DeepEqual(existingMedia.Countries, newMedia.Countries) ||
-		!reflect.DeepEqual(existingMedia.Genres, newMedia.Genres)
+		!reflect.DeepEqual(existingMedia.Genres, newMedia.Genres) ||
+		!slices.Equal(existingMedia.CountryList, newMedia.CountryList) ||
+		!slices.Equal(existingMedia.GenreList, newMedia.GenreList)
 }
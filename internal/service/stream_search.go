@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/watchlist-kata/media/internal/audit"
+	"github.com/watchlist-kata/media/internal/repository"
+	"github.com/watchlist-kata/media/internal/validation"
+	"github.com/watchlist-kata/protos/media"
+)
+
+// kinopoiskResult - результат поиска в Кинопоиске, выполняемого параллельно с локальным
+type kinopoiskResult struct {
+	medias []*media.Media
+	err    error
+}
+
+// SearchMediaStream ищет медиа по названию и отправляет результаты по мере готовности:
+// сначала локальные, затем из Кинопоиска после сохранения в базу. Источники выбираются
+// режимом req.Mode. Поток не сортируется, у каждого результата есть оценка релевантности.
+// Последнее сообщение - итог поиска с признаком успешности запроса в Кинопоиск
+func (s *MediaService) SearchMediaStream(ctx context.Context, req *media.GetMediasByNameRequest, send func(*media.SearchMediaStreamResponse) error) error {
+	if req == nil {
+		return fmt.Errorf("invalid request: nil pointer")
+	}
+
+	s.logger.InfoContext(ctx, "SearchMediaStream called", "name", req.Name, "mode", req.Mode, "skip_persistence", req.SkipPersistence)
+
+	if req.Name == "" {
+		verr := &validation.Error{}
+		verr.Add("name", "must not be empty")
+		return verr
+	}
+
+	plan, err := newSearchPlan(req)
+	if err != nil {
+		return err
+	}
+
+	// Кинопоиск отвечает дольше базы, поэтому запрашиваем его сразу
+	kpCtx, kpCancel := kinopoiskContext(ctx)
+	defer kpCancel()
+	kpDone := make(chan kinopoiskResult, 1)
+	if plan.remote {
+		go func() {
+			medias, err := s.SearchKinopoisk(kpCtx, req.Name)
+			kpDone <- kinopoiskResult{medias: medias, err: err}
+		}()
+	}
+
+	summary := &media.SearchSummary{UpstreamSkipped: !plan.remote}
+	emitted := make(map[int64]*media.Media)
+	query := newRelevanceQuery(req.Name)
+	sendHit := func(m *media.Media, source media.MediaSource) error {
+		emitted[m.KinopoiskId] = m
+		return send(&media.SearchMediaStreamResponse{
+			Result: &media.SearchMediaStreamResponse_Hit{Hit: &media.SearchHit{Media: m, Source: source, Score: query.score(m, source)}},
+		})
+	}
+
+	if plan.local {
+		localMedias, err := s.repo.GetMediasByNameFromRepo(ctx, req.Name, s.searchMode)
+		if err != nil {
+			return s.handleError(ctx, "Failed to SearchMediaStream in DB", fmt.Errorf("failed to get medias by name %s from DB: %w", req.Name, err), "name", req.Name, "error", err)
+		}
+		for _, m := range localMedias {
+			if _, exists := emitted[m.KinopoiskId]; exists {
+				continue
+			}
+			if err := sendHit(m, media.MediaSource_MEDIA_SOURCE_LOCAL); err != nil {
+				return err
+			}
+			summary.LocalCount++
+		}
+	}
+
+	var kp kinopoiskResult
+	if plan.remote {
+		select {
+		case kp = <-kpDone:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	if kp.err != nil {
+		summary.UpstreamError = kp.err.Error()
+	} else if plan.remote {
+		summary.UpstreamOk = true
+		syncCtx := audit.WithActor(ctx, audit.ActorKinopoiskSync)
+		for _, kpMedia := range kp.medias {
+			m, changed := kpMedia, true
+			if _, exists := emitted[kpMedia.KinopoiskId]; exists && !plan.persist {
+				continue
+			}
+			if plan.persist {
+				// Совпадения с локальными результатами отправляем, только если запись обновилась
+				m, changed = s.syncKinopoiskMedia(ctx, syncCtx, kpMedia, emitted[kpMedia.KinopoiskId])
+			}
+			if !changed {
+				continue
+			}
+			if err := sendHit(m, media.MediaSource_MEDIA_SOURCE_KINOPOISK); err != nil {
+				return err
+			}
+			summary.UpstreamCount++
+		}
+	}
+
+	// Если ничего не нашлось, пробуем нечеткий поиск с учетом опечаток
+	if plan.local && len(emitted) == 0 && s.searchMode != repository.SearchModeTrigram {
+		s.logger.InfoContext(ctx, "No exact matches, falling back to fuzzy search", "name", req.Name)
+		fuzzyMedias, err := s.repo.GetMediasByNameFromRepo(ctx, req.Name, repository.SearchModeTrigram)
+		if err != nil {
+			return s.handleError(ctx, "Failed to fuzzy search media in DB", fmt.Errorf("failed to fuzzy search medias by name %s in DB: %w", req.Name, err), "name", req.Name, "error", err)
+		}
+		for _, m := range fuzzyMedias {
+			if err := sendHit(m, media.MediaSource_MEDIA_SOURCE_LOCAL); err != nil {
+				return err
+			}
+			summary.LocalCount++
+		}
+	}
+
+	s.logger.InfoContext(ctx, "SearchMediaStream successful", "local", summary.LocalCount, "upstream", summary.UpstreamCount, "upstream_ok", summary.UpstreamOk)
+	return send(&media.SearchMediaStreamResponse{
+		Result: &media.SearchMediaStreamResponse_Summary{Summary: summary},
+	})
+}
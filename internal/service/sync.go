@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/watchlist-kata/media/internal/audit"
+	"github.com/watchlist-kata/media/internal/repository"
+	"github.com/watchlist-kata/media/internal/validation"
+	"github.com/watchlist-kata/protos/media"
+)
+
+// syncContext возвращает контекст, в котором изменения записываются от имени синхронизации
+// с Кинопоиском, если автор не задан вызывающим
+func syncContext(ctx context.Context) context.Context {
+	if audit.ActorFrom(ctx) == audit.ActorAnonymous {
+		return audit.WithActor(ctx, audit.ActorKinopoiskSync)
+	}
+	return ctx
+}
+
+// validateKinopoiskID проверяет ID Кинопоиска из запроса
+func validateKinopoiskID(kinopoiskID int64) error {
+	verr := &validation.Error{}
+	if kinopoiskID <= 0 {
+		verr.Add("kinopoisk_id", "must be greater than 0")
+	}
+	return verr.Err()
+}
+
+// fetchKinopoisk получает фильм из Кинопоиска и проверяет его перед сохранением
+func (s *MediaService) fetchKinopoisk(ctx context.Context, kinopoiskID int64) (*media.Media, error) {
+	kpCtx, kpCancel := kinopoiskContext(ctx)
+	defer kpCancel()
+
+	kpMedia, err := s.kinopoiskClient.GetByID(kpCtx, kinopoiskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get film %d from Kinopoisk: %w", kinopoiskID, err)
+	}
+	if err := validation.ValidateMedia(kpMedia); err != nil {
+		s.logger.WarnContext(ctx, "Invalid media from Kinopoisk", "kinopoiskID", kinopoiskID, "error", err)
+		return nil, fmt.Errorf("invalid media from Kinopoisk: %w", err)
+	}
+	return kpMedia, nil
+}
+
+// ImportMedia возвращает медиа с заданным ID Кинопоиска, при отсутствии загружая его из Кинопоиска.
+// Повторный вызов с тем же ID возвращает уже сохраненную запись
+func (s *MediaService) ImportMedia(ctx context.Context, kinopoiskID int64) (*media.Media, error) {
+	s.logger.InfoContext(ctx, "ImportMedia called", "kinopoiskID", kinopoiskID)
+
+	if err := validateKinopoiskID(kinopoiskID); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.GetMediaByKinopoiskID(ctx, kinopoiskID)
+	if err == nil {
+		s.logger.InfoContext(ctx, "Media already imported", "kinopoiskID", kinopoiskID, "id", existing.Id)
+		return existing, nil
+	}
+	if !errors.Is(err, repository.ErrMediaNotFound) {
+		return nil, s.handleError(ctx, "Failed to ImportMedia", fmt.Errorf("failed to check media with kinopoisk_id %d: %w", kinopoiskID, err), "kinopoiskID", kinopoiskID, "error", err)
+	}
+
+	kpMedia, err := s.fetchKinopoisk(ctx, kinopoiskID)
+	if err != nil {
+		return nil, s.handleError(ctx, "Failed to ImportMedia", err, "kinopoiskID", kinopoiskID, "error", err)
+	}
+
+	now := time.Now().Format(time.RFC3339)
+	kpMedia.CreatedAt, kpMedia.UpdatedAt = now, now
+	created, err := s.repo.CreateMedia(syncContext(ctx), kpMedia)
+	if errors.Is(err, repository.ErrDuplicateKinopoiskID) {
+		// Медиа сохранили параллельно - возвращаем сохраненную запись
+		if existing, getErr := s.repo.GetMediaByKinopoiskID(ctx, kinopoiskID); getErr == nil {
+			return existing, nil
+		}
+	}
+	if err != nil {
+		return nil, s.handleError(ctx, "Failed to ImportMedia", fmt.Errorf("failed to save media with kinopoisk_id %d: %w", kinopoiskID, err), "kinopoiskID", kinopoiskID, "error", err)
+	}
+
+	s.logger.InfoContext(ctx, "Media imported from Kinopoisk", "kinopoiskID", kinopoiskID, "id", created.Id)
+	s.publishChange(created)
+	return created, nil
+}
+
+// RefreshMedia обновляет сохраненное медиа данными из Кинопоиска.
+// Все поля перезаписываются, поэтому ручные правки через UpdateMedia теряются
+func (s *MediaService) RefreshMedia(ctx context.Context, kinopoiskID int64) (*media.Media, error) {
+	s.logger.InfoContext(ctx, "RefreshMedia called", "kinopoiskID", kinopoiskID)
+
+	if err := validateKinopoiskID(kinopoiskID); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.GetMediaByKinopoiskID(ctx, kinopoiskID)
+	if err != nil {
+		return nil, s.handleError(ctx, "Failed to RefreshMedia", fmt.Errorf("failed to get media with kinopoisk_id %d: %w", kinopoiskID, err), "kinopoiskID", kinopoiskID, "error", err)
+	}
+
+	kpMedia, err := s.fetchKinopoisk(ctx, kinopoiskID)
+	if err != nil {
+		return nil, s.handleError(ctx, "Failed to RefreshMedia", err, "kinopoiskID", kinopoiskID, "error", err)
+	}
+
+	if !needsUpdate(existing, kpMedia) {
+		s.logger.InfoContext(ctx, "Media is up to date", "kinopoiskID", kinopoiskID, "id", existing.Id)
+		s.touchMedia(syncContext(ctx), existing)
+		return existing, nil
+	}
+
+	kpMedia.Id = existing.Id
+	kpMedia.Version = existing.Version
+	updated, err := s.repo.UpdateMedia(syncContext(ctx), kpMedia, nil)
+	if err != nil {
+		return nil, s.handleError(ctx, "Failed to RefreshMedia", fmt.Errorf("failed to update media with kinopoisk_id %d: %w", kinopoiskID, err), "kinopoiskID", kinopoiskID, "error", err)
+	}
+
+	s.logger.InfoContext(ctx, "Media refreshed from Kinopoisk", "kinopoiskID", kinopoiskID, "id", updated.Id, "version", updated.Version)
+	s.publishChange(updated)
+	return updated, nil
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/watchlist-kata/media/internal/kinopoisk"
+	"github.com/watchlist-kata/media/internal/repository"
+	"github.com/watchlist-kata/media/internal/validation"
+	"github.com/watchlist-kata/protos/media"
+	"google.golang.org/protobuf/proto"
+)
+
+// fakeRepository отвечает на поиск по kinopoisk_id по очереди из lookups и считает вызовы CreateMedia.
+// Остальные методы Repository не реализованы и паникуют при вызове
+type fakeRepository struct {
+	repository.Repository
+	lookups   []lookupResult
+	createErr error
+	created   int
+}
+
+// lookupResult - ответ на один вызов GetMediaByKinopoiskID
+type lookupResult struct {
+	media *media.Media
+	err   error
+}
+
+func (r *fakeRepository) GetMediaByKinopoiskID(ctx context.Context, kinopoiskID int64) (*media.Media, error) {
+	if len(r.lookups) == 0 {
+		return nil, repository.ErrMediaNotFound
+	}
+	next := r.lookups[0]
+	r.lookups = r.lookups[1:]
+	return next.media, next.err
+}
+
+func (r *fakeRepository) CreateMedia(ctx context.Context, m *media.Media) (*media.Media, error) {
+	r.created++
+	if r.createErr != nil {
+		return nil, r.createErr
+	}
+	saved := proto.Clone(m).(*media.Media)
+	saved.Id, saved.Version = 100, 1
+	return saved, nil
+}
+
+// fakeKinopoisk возвращает film или err на запрос фильма и считает запросы
+type fakeKinopoisk struct {
+	film  *media.Media
+	err   error
+	calls int
+}
+
+func (k *fakeKinopoisk) SearchByKeyword(ctx context.Context, keyword string) ([]*media.Media, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (k *fakeKinopoisk) GetByID(ctx context.Context, kinopoiskID int64) (*media.Media, error) {
+	k.calls++
+	if k.err != nil {
+		return nil, k.err
+	}
+	return proto.Clone(k.film).(*media.Media), nil
+}
+
+func TestImportMedia(t *testing.T) {
+	const kinopoiskID = 301
+	film := &media.Media{KinopoiskId: kinopoiskID, Type: "FILM", NameRu: "Матрица", Year: "1999"}
+	errDatabase := errors.New("connection refused")
+	stored := &media.Media{Id: 7, Version: 3, KinopoiskId: kinopoiskID, Type: "FILM", NameRu: "Матрица (правка)"}
+
+	tests := []struct {
+		name           string
+		kinopoiskID    int64
+		repo           *fakeRepository
+		client         *fakeKinopoisk
+		wantID         int64
+		wantErr        error
+		wantValidation bool
+		wantKPCalls    int
+		wantCreates    int
+	}{
+		{
+			name:        "already imported",
+			kinopoiskID: kinopoiskID,
+			repo:        &fakeRepository{lookups: []lookupResult{{media: stored}}},
+			client:      &fakeKinopoisk{film: film},
+			wantID:      stored.Id,
+		},
+		{
+			name:        "new media",
+			kinopoiskID: kinopoiskID,
+			repo:        &fakeRepository{},
+			client:      &fakeKinopoisk{film: film},
+			wantID:      100,
+			wantKPCalls: 1,
+			wantCreates: 1,
+		},
+		{
+			name:        "saved concurrently",
+			kinopoiskID: kinopoiskID,
+			repo: &fakeRepository{
+				lookups:   []lookupResult{{err: repository.ErrMediaNotFound}, {media: stored}},
+				createErr: repository.ErrDuplicateKinopoiskID,
+			},
+			client:      &fakeKinopoisk{film: film},
+			wantID:      stored.Id,
+			wantKPCalls: 1,
+			wantCreates: 1,
+		},
+		{
+			name:        "saved concurrently and deleted again",
+			kinopoiskID: kinopoiskID,
+			repo: &fakeRepository{
+				lookups:   []lookupResult{{err: repository.ErrMediaNotFound}, {err: repository.ErrMediaNotFound}},
+				createErr: repository.ErrDuplicateKinopoiskID,
+			},
+			client:      &fakeKinopoisk{film: film},
+			wantErr:     repository.ErrDuplicateKinopoiskID,
+			wantKPCalls: 1,
+			wantCreates: 1,
+		},
+		{
+			name:        "film not found in Kinopoisk",
+			kinopoiskID: kinopoiskID,
+			repo:        &fakeRepository{},
+			client:      &fakeKinopoisk{err: kinopoisk.ErrFilmNotFound},
+			wantErr:     kinopoisk.ErrFilmNotFound,
+			wantKPCalls: 1,
+		},
+		{
+			name:        "lookup fails",
+			kinopoiskID: kinopoiskID,
+			repo:        &fakeRepository{lookups: []lookupResult{{err: errDatabase}}},
+			client:      &fakeKinopoisk{film: film},
+			wantErr:     errDatabase,
+		},
+		{
+			name:           "invalid kinopoisk id",
+			kinopoiskID:    0,
+			repo:           &fakeRepository{},
+			client:         &fakeKinopoisk{film: film},
+			wantValidation: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &MediaService{
+				repo:            tt.repo,
+				logger:          slog.New(slog.NewTextHandler(io.Discard, nil)),
+				kinopoiskClient: tt.client,
+				changes:         NewChangeBroadcaster(),
+			}
+
+			got, err := s.ImportMedia(context.Background(), tt.kinopoiskID)
+			switch {
+			case tt.wantValidation:
+				var verr *validation.Error
+				if !errors.As(err, &verr) {
+					t.Fatalf("error = %v, want *validation.Error", err)
+				}
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+			default:
+				if err != nil {
+					t.Fatalf("ImportMedia failed: %v", err)
+				}
+				if got.Id != tt.wantID {
+					t.Errorf("media id = %d, want %d", got.Id, tt.wantID)
+				}
+			}
+
+			if tt.client.calls != tt.wantKPCalls {
+				t.Errorf("Kinopoisk calls = %d, want %d", tt.client.calls, tt.wantKPCalls)
+			}
+			if tt.repo.created != tt.wantCreates {
+				t.Errorf("CreateMedia calls = %d, want %d", tt.repo.created, tt.wantCreates)
+			}
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package validation
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/watchlist-kata/protos/media"
+)
+
+// Ограничения длины, совпадающие с размерами varchar в GormMedia
+const (
+	MaxNameLength      = 255
+	MaxPosterLength    = 255
+	MaxReferenceLength = 100 // Длина названия жанра или страны
+)
+
+// MediaTypes - допустимые значения поля Type (значения Кинопоиска)
+var MediaTypes = map[string]struct{}{
+	"FILM":        {},
+	"TV_SERIES":   {},
+	"MINI_SERIES": {},
+	"TV_SHOW":     {},
+	"VIDEO":       {},
+	"UNKNOWN":     {},
+}
+
+// UpdatableFields - поля медиа, которые можно передать в update_mask
+var UpdatableFields = map[string]struct{}{
+	"type":         {},
+	"name_en":      {},
+	"name_ru":      {},
+	"description":  {},
+	"year":         {},
+	"poster":       {},
+	"countries":    {},
+	"genres":       {},
+	"genre_list":   {},
+	"country_list": {},
+}
+
+// yearPattern - год "2010" или диапазон лет сериала "2010-2015"
+var yearPattern = regexp.MustCompile(`^(\d{4})(?:-(\d{4}))?$`)
+
+// FieldViolation описывает нарушение для одного поля.
+// Field - путь к полю в запросе, например "media.poster"
+type FieldViolation struct {
+	Field       string
+	Description string
+}
+
+// Error содержит все нарушения валидации
+type Error struct {
+	Violations []FieldViolation
+}
+
+// Error реализует интерфейс error
+func (e *Error) Error() string {
+	parts := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		parts = append(parts, v.Field+": "+v.Description)
+	}
+	return "validation failed: " + strings.Join(parts, "; ")
+}
+
+// Add добавляет нарушение для поля
+func (e *Error) Add(field, description string) {
+	e.Violations = append(e.Violations, FieldViolation{Field: field, Description: description})
+}
+
+// Err возвращает ошибку, если есть нарушения, иначе nil
+func (e *Error) Err() error {
+	if len(e.Violations) == 0 {
+		return nil
+	}
+	return e
+}
+
+// ValidateMedia проверяет медиа перед созданием
+func ValidateMedia(m *media.Media) error {
+	verr := &Error{}
+	validateMedia(verr, m)
+	return verr.Err()
+}
+
+// ValidateMediaUpdate проверяет медиа перед обновлением, дополнительно требуя ID и версию.
+// Если задан paths из update_mask, проверяются только эти поля: остальные берутся из
+// сохраненной записи и могут не проходить текущие правила, например старые типы movie/tv
+func ValidateMediaUpdate(m *media.Media, paths []string) error {
+	verr := &Error{}
+	if m != nil && m.Id <= 0 {
+		verr.Add("media.id", "must be greater than 0")
+	}
+	if m != nil && m.Version <= 0 {
+		verr.Add("media.version", "must be greater than 0")
+	}
+	if len(paths) == 0 {
+		validateMedia(verr, m)
+		return verr.Err()
+	}
+
+	fields := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		fields[path] = true
+	}
+	validateFields(verr, m, fields)
+	return verr.Err()
+}
+
+// ValidateUpdateMask проверяет, что все пути маски известны и обновляемы
+func ValidateUpdateMask(paths []string) error {
+	verr := &Error{}
+	for _, path := range paths {
+		if _, ok := UpdatableFields[path]; !ok {
+			verr.Add("update_mask.paths", fmt.Sprintf("unknown or read-only field %q", path))
+		}
+	}
+	return verr.Err()
+}
+
+// validateMedia добавляет в verr нарушения для всех полей медиа
+func validateMedia(verr *Error, m *media.Media) {
+	validateFields(verr, m, nil)
+}
+
+// validateFields добавляет в verr нарушения для полей из fields, nil - для всех полей
+func validateFields(verr *Error, m *media.Media, fields map[string]bool) {
+	if m == nil {
+		verr.Add("media", "media cannot be nil")
+		return
+	}
+	has := func(field string) bool { return fields == nil || fields[field] }
+
+	if fields == nil && m.KinopoiskId <= 0 {
+		verr.Add("media.kinopoisk_id", "must be greater than 0")
+	}
+
+	if _, ok := MediaTypes[m.Type]; has("type") && !ok {
+		verr.Add("media.type", fmt.Sprintf("unknown type %q", m.Type))
+	}
+
+	if has("name_ru") || has("name_en") {
+		if strings.TrimSpace(m.NameRu) == "" && strings.TrimSpace(m.NameEn) == "" {
+			verr.Add("media.name_ru", "name_ru or name_en must be set")
+		}
+	}
+	if has("name_ru") {
+		checkLength(verr, "media.name_ru", m.NameRu, MaxNameLength)
+	}
+	if has("name_en") {
+		checkLength(verr, "media.name_en", m.NameEn, MaxNameLength)
+	}
+
+	if has("year") && m.Year != "" {
+		if err := validateYear(m.Year); err != nil {
+			verr.Add("media.year", err.Error())
+		}
+	}
+
+	if has("poster") && m.Poster != "" {
+		if err := validatePosterURL(m.Poster); err != nil {
+			verr.Add("media.poster", err.Error())
+		}
+		checkLength(verr, "media.poster", m.Poster, MaxPosterLength)
+	}
+
+	if has("genre_list") {
+		for i, genre := range m.GenreList {
+			checkLength(verr, fmt.Sprintf("media.genre_list[%d]", i), genre, MaxReferenceLength)
+		}
+	}
+	if has("country_list") {
+		for i, country := range m.CountryList {
+			checkLength(verr, fmt.Sprintf("media.country_list[%d]", i), country, MaxReferenceLength)
+		}
+	}
+}
+
+// checkLength проверяет длину строки в символах, как ее считает varchar в Postgres
+func checkLength(verr *Error, field, value string, limit int) {
+	if n := utf8.RuneCountInString(value); n > limit {
+		verr.Add(field, fmt.Sprintf("must be at most %d characters, got %d", limit, n))
+	}
+}
+
+// validateYear проверяет формат года или диапазона лет
+func validateYear(year string) error {
+	match := yearPattern.FindStringSubmatch(year)
+	if match == nil {
+		return fmt.Errorf("must be YYYY or YYYY-YYYY, got %q", year)
+	}
+	if match[2] != "" {
+		start, _ := strconv.Atoi(match[1])
+		end, _ := strconv.Atoi(match[2])
+		if end < start {
+			return fmt.Errorf("range end %d is before start %d", end, start)
+		}
+	}
+	return nil
+}
+
+// validatePosterURL проверяет, что постер - абсолютный http(s) URL
+func validatePosterURL(poster string) error {
+	u, err := url.Parse(poster)
+	if err != nil {
+		return fmt.Errorf("invalid URL: %v", err)
+	}
+	if !u.IsAbs() || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("must be an absolute http(s) URL")
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package validation
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/watchlist-kata/protos/media"
+)
+
+// validMedia возвращает медиа, которое проходит все проверки
+func validMedia() *media.Media {
+	return &media.Media{
+		Id:          1,
+		Version:     1,
+		KinopoiskId: 301,
+		Type:        "FILM",
+		NameRu:      "Матрица",
+		NameEn:      "The Matrix",
+		Year:        "1999",
+		Poster:      "https://kinopoiskapiunofficial.tech/images/posters/kp/301.jpg",
+		GenreList:   []string{"фантастика", "боевик"},
+		CountryList: []string{"США"},
+	}
+}
+
+// violatedFields возвращает поля из нарушений валидации, nil - ошибки нет
+func violatedFields(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var verr *Error
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected *validation.Error, got %T: %v", err, err)
+	}
+	fields := make([]string, 0, len(verr.Violations))
+	for _, v := range verr.Violations {
+		fields = append(fields, v.Field)
+	}
+	return fields
+}
+
+func TestValidateMedia(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(m *media.Media)
+		want   []string
+	}{
+		{name: "valid", modify: func(m *media.Media) {}},
+		{name: "only english name", modify: func(m *media.Media) { m.NameRu = "" }},
+		{name: "empty year and poster", modify: func(m *media.Media) { m.Year, m.Poster = "", "" }},
+		{name: "year range", modify: func(m *media.Media) { m.Year = "2010-2015" }},
+		{name: "year range of one year", modify: func(m *media.Media) { m.Year = "2010-2010" }},
+		{name: "name at length limit", modify: func(m *media.Media) { m.NameRu = strings.Repeat("я", MaxNameLength) }},
+		{name: "missing kinopoisk id", modify: func(m *media.Media) { m.KinopoiskId = 0 }, want: []string{"media.kinopoisk_id"}},
+		{name: "negative kinopoisk id", modify: func(m *media.Media) { m.KinopoiskId = -1 }, want: []string{"media.kinopoisk_id"}},
+		{name: "legacy type", modify: func(m *media.Media) { m.Type = "movie" }, want: []string{"media.type"}},
+		{name: "empty type", modify: func(m *media.Media) { m.Type = "" }, want: []string{"media.type"}},
+		{name: "no names", modify: func(m *media.Media) { m.NameRu, m.NameEn = "", " " }, want: []string{"media.name_ru"}},
+		{name: "name over length limit", modify: func(m *media.Media) { m.NameEn = strings.Repeat("a", MaxNameLength+1) }, want: []string{"media.name_en"}},
+		{name: "short year", modify: func(m *media.Media) { m.Year = "99" }, want: []string{"media.year"}},
+		{name: "reversed year range", modify: func(m *media.Media) { m.Year = "2015-2010" }, want: []string{"media.year"}},
+		{name: "open year range", modify: func(m *media.Media) { m.Year = "2010-" }, want: []string{"media.year"}},
+		{name: "relative poster", modify: func(m *media.Media) { m.Poster = "/posters/301.jpg" }, want: []string{"media.poster"}},
+		{name: "ftp poster", modify: func(m *media.Media) { m.Poster = "ftp://example.com/301.jpg" }, want: []string{"media.poster"}},
+		{name: "long poster", modify: func(m *media.Media) { m.Poster = "https://example.com/" + strings.Repeat("p", MaxPosterLength) }, want: []string{"media.poster"}},
+		{name: "long genre", modify: func(m *media.Media) { m.GenreList[1] = strings.Repeat("g", MaxReferenceLength+1) }, want: []string{"media.genre_list[1]"}},
+		{name: "long country", modify: func(m *media.Media) { m.CountryList[0] = strings.Repeat("c", MaxReferenceLength+1) }, want: []string{"media.country_list[0]"}},
+		{
+			name:   "all violations are reported",
+			modify: func(m *media.Media) { m.KinopoiskId, m.Type, m.Year = 0, "", "soon" },
+			want:   []string{"media.kinopoisk_id", "media.type", "media.year"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := validMedia()
+			tt.modify(m)
+			if got := violatedFields(t, ValidateMedia(m)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("violations = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("nil media", func(t *testing.T) {
+		if got := violatedFields(t, ValidateMedia(nil)); !reflect.DeepEqual(got, []string{"media"}) {
+			t.Errorf("violations = %v, want [media]", got)
+		}
+	})
+}
+
+func TestValidateMediaUpdate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(m *media.Media)
+		paths  []string
+		want   []string
+	}{
+		{name: "valid full update", modify: func(m *media.Media) {}},
+		{name: "missing id and version", modify: func(m *media.Media) { m.Id, m.Version = 0, 0 }, want: []string{"media.id", "media.version"}},
+		{name: "legacy type in full update", modify: func(m *media.Media) { m.Type = "tv" }, want: []string{"media.type"}},
+		{name: "legacy type outside mask", modify: func(m *media.Media) { m.Type = "tv" }, paths: []string{"description"}},
+		{name: "legacy type in mask", modify: func(m *media.Media) { m.Type = "tv" }, paths: []string{"type"}, want: []string{"media.type"}},
+		{name: "invalid poster outside mask", modify: func(m *media.Media) { m.Poster = "poster.jpg" }, paths: []string{"year"}},
+		{name: "invalid year in mask", modify: func(m *media.Media) { m.Year = "19999" }, paths: []string{"year"}, want: []string{"media.year"}},
+		{name: "clearing the only name", modify: func(m *media.Media) { m.NameRu, m.NameEn = "", "" }, paths: []string{"name_en"}, want: []string{"media.name_ru"}},
+		{name: "kinopoisk id is not checked for masks", modify: func(m *media.Media) { m.KinopoiskId = 0 }, paths: []string{"name_ru"}},
+		{name: "mask still requires version", modify: func(m *media.Media) { m.Version = 0 }, paths: []string{"name_ru"}, want: []string{"media.version"}},
+		{name: "long genre in mask", modify: func(m *media.Media) { m.GenreList[0] = strings.Repeat("g", MaxReferenceLength+1) }, paths: []string{"genre_list"}, want: []string{"media.genre_list[0]"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := validMedia()
+			tt.modify(m)
+			if got := violatedFields(t, ValidateMediaUpdate(m, tt.paths)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("violations = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateUpdateMask(t *testing.T) {
+	tests := []struct {
+		name  string
+		paths []string
+		want  []string
+	}{
+		{name: "empty mask", paths: nil},
+		{name: "updatable fields", paths: []string{"name_ru", "genre_list", "year"}},
+		{name: "read-only field", paths: []string{"kinopoisk_id"}, want: []string{"update_mask.paths"}},
+		{name: "unknown field", paths: []string{"rating", "name_ru", "id"}, want: []string{"update_mask.paths", "update_mask.paths"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := violatedFields(t, ValidateUpdateMask(tt.paths)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("violations = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -239,6 +239,14 @@ func NewStdoutHandler() *StdoutHandler {
 	}
 }
 
+// NewStderrHandler initializes a StdoutHandler that writes to stderr,
+// leaving stdout for command output.
+func NewStderrHandler() *StdoutHandler {
+	return &StdoutHandler{
+		writer: os.Stderr,
+	}
+}
+
 // Enabled checks if the level is enabled.
 func (s *StdoutHandler) Enabled(ctx context.Context, level slog.Level) bool {
 	return true
@@ -286,19 +294,38 @@ func (s *StdoutHandler) Close() error {
 }
 
 // MultiHandler combines multiple handlers.
+// The minimum level is shared by all handlers and by every handler derived
+// through WithAttrs and WithGroup, so it can be changed at runtime.
 type MultiHandler struct {
 	handlers []slog.Handler
+	level    *slog.LevelVar
 }
 
 // NewMultiHandler initializes a new MultiHandler.
 func NewMultiHandler(handlers ...slog.Handler) *MultiHandler {
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelDebug)
 	return &MultiHandler{
 		handlers: handlers,
+		level:    level,
 	}
 }
 
+// SetLevel changes the minimum level for all handlers.
+func (m *MultiHandler) SetLevel(level slog.Level) {
+	m.level.Set(level)
+}
+
+// Level returns the current minimum level.
+func (m *MultiHandler) Level() slog.Level {
+	return m.level.Level()
+}
+
 // Enabled checks if the level is enabled for any handler.
 func (m *MultiHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if level < m.level.Level() {
+		return false
+	}
 	for _, h := range m.handlers {
 		if h.Enabled(ctx, level) {
 			return true
@@ -324,7 +351,7 @@ func (m *MultiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
 	for i, h := range m.handlers {
 		handlers[i] = h.WithAttrs(attrs)
 	}
-	return NewMultiHandler(handlers...)
+	return &MultiHandler{handlers: handlers, level: m.level}
 }
 
 // WithGroup adds a group to all handlers.
@@ -333,7 +360,7 @@ func (m *MultiHandler) WithGroup(name string) slog.Handler {
 	for i, h := range m.handlers {
 		handlers[i] = h.WithGroup(name)
 	}
-	return NewMultiHandler(handlers...)
+	return &MultiHandler{handlers: handlers, level: m.level}
 }
 
 // CloseAll closes all handlers that implement the Close method.
@@ -346,7 +373,7 @@ func (m *MultiHandler) CloseAll() {
 }
 
 // NewLogger initializes the combined logger with Kafka, File, and Stdout handlers.
-func NewLogger(brokers []string, kafkaTopic, serviceName string, bufferSize int) (*slog.Logger, error) {
+func NewLogger(brokers []string, kafkaTopic, serviceName string, bufferSize int, level slog.Level) (*slog.Logger, error) {
 	kafkaHandler, err := NewKafkaHandler(brokers, kafkaTopic, bufferSize)
 	if err != nil {
 		return nil, err
@@ -361,6 +388,7 @@ func NewLogger(brokers []string, kafkaTopic, serviceName string, bufferSize int)
 	stdoutHandler := NewStdoutHandler()
 
 	multiHandler := NewMultiHandler(kafkaHandler, fileHandler, stdoutHandler)
+	multiHandler.SetLevel(level)
 
 	logger := slog.New(multiHandler)
 
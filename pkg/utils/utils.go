@@ -9,6 +9,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/IBM/sarama"
 	"github.com/watchlist-kata/media/internal/config"
 	"github.com/watchlist-kata/media/pkg/logger"
 	"google.golang.org/grpc"
@@ -23,6 +24,7 @@ func NewCustomLogger(cfg *config.Config) (*slog.Logger, error) {
 		cfg.KafkaTopic,
 		cfg.ServiceName,
 		cfg.LogBufferSize,
+		cfg.LogLevel,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize logger: %w", err)
@@ -34,7 +36,7 @@ func NewCustomLogger(cfg *config.Config) (*slog.Logger, error) {
 func NewDatabaseConnection(cfg *config.Config) (*gorm.DB, Closer, error) {
 	dsn := fmt.Sprintf("host=%s port=%s user=%s dbname=%s sslmode=%s password=%s",
 		cfg.DBHost, cfg.DBPort, cfg.DBUser, cfg.DBName, cfg.DBSSLMode, cfg.DBPassword)
-	db, err := gorm.Open(pg.Open(dsn), &gorm.Config{})
+	db, err := gorm.Open(pg.Open(dsn), &gorm.Config{TranslateError: true})
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
@@ -47,6 +49,23 @@ func NewDatabaseConnection(cfg *config.Config) (*gorm.DB, Closer, error) {
 	return db, sqlDB, nil
 }
 
+// NewEventProducer creates a Kafka producer for media change events.
+// Messages are acknowledged by all in-sync replicas and partitioned by key.
+func NewEventProducer(cfg *config.Config) (sarama.SyncProducer, error) {
+	saramaCfg := sarama.NewConfig()
+	saramaCfg.Producer.RequiredAcks = sarama.WaitForAll
+	saramaCfg.Producer.Retry.Max = 5
+	saramaCfg.Producer.Return.Successes = true
+	saramaCfg.Producer.Return.Errors = true
+	saramaCfg.Producer.Partitioner = sarama.NewHashPartitioner
+
+	producer, err := sarama.NewSyncProducer(cfg.KafkaBrokers, saramaCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create event producer: %w", err)
+	}
+	return producer, nil
+}
+
 // CloseLogger safely closes the logger handlers.
 func CloseLogger(customLogger *slog.Logger) {
 	multiHandler := customLogger.Handler()
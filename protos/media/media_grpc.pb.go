@@ -0,0 +1,627 @@
+// protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative media.proto
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v3.21.12
+// source: media.proto
+
+package media
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	MediaService_GetMediaByID_FullMethodName      = "/media.MediaService/GetMediaByID"
+	MediaService_GetMediasByName_FullMethodName   = "/media.MediaService/GetMediasByName"
+	MediaService_SaveMedia_FullMethodName         = "/media.MediaService/SaveMedia"
+	MediaService_UpdateMedia_FullMethodName       = "/media.MediaService/UpdateMedia"
+	MediaService_SearchKinopoisk_FullMethodName   = "/media.MediaService/SearchKinopoisk"
+	MediaService_DeleteMedia_FullMethodName       = "/media.MediaService/DeleteMedia"
+	MediaService_ListMedia_FullMethodName         = "/media.MediaService/ListMedia"
+	MediaService_RestoreMedia_FullMethodName      = "/media.MediaService/RestoreMedia"
+	MediaService_PurgeMedia_FullMethodName        = "/media.MediaService/PurgeMedia"
+	MediaService_GetMediaHistory_FullMethodName   = "/media.MediaService/GetMediaHistory"
+	MediaService_WatchMediaChanges_FullMethodName = "/media.MediaService/WatchMediaChanges"
+	MediaService_SearchMediaStream_FullMethodName = "/media.MediaService/SearchMediaStream"
+	MediaService_ImportMedia_FullMethodName       = "/media.MediaService/ImportMedia"
+	MediaService_ExportMedia_FullMethodName       = "/media.MediaService/ExportMedia"
+)
+
+// MediaServiceClient is the client API for MediaService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type MediaServiceClient interface {
+	GetMediaByID(ctx context.Context, in *GetMediaByIDRequest, opts ...grpc.CallOption) (*Media, error)
+	GetMediasByName(ctx context.Context, in *GetMediasByNameRequest, opts ...grpc.CallOption) (*MediaList, error)
+	SaveMedia(ctx context.Context, in *SaveMediaRequest, opts ...grpc.CallOption) (*Media, error)
+	UpdateMedia(ctx context.Context, in *SaveMediaRequest, opts ...grpc.CallOption) (*Media, error)
+	SearchKinopoisk(ctx context.Context, in *SearchKinopoiskRequest, opts ...grpc.CallOption) (*MediaList, error)
+	DeleteMedia(ctx context.Context, in *DeleteMediaRequest, opts ...grpc.CallOption) (*DeleteMediaResponse, error)
+	ListMedia(ctx context.Context, in *ListMediaRequest, opts ...grpc.CallOption) (*ListMediaResponse, error)
+	RestoreMedia(ctx context.Context, in *RestoreMediaRequest, opts ...grpc.CallOption) (*Media, error)
+	PurgeMedia(ctx context.Context, in *PurgeMediaRequest, opts ...grpc.CallOption) (*DeleteMediaResponse, error)
+	GetMediaHistory(ctx context.Context, in *GetMediaHistoryRequest, opts ...grpc.CallOption) (*GetMediaHistoryResponse, error)
+	WatchMediaChanges(ctx context.Context, in *WatchMediaChangesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[MediaHistoryEntry], error)
+	SearchMediaStream(ctx context.Context, in *GetMediasByNameRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SearchMediaStreamResponse], error)
+	ImportMedia(ctx context.Context, in *ImportMediaRequest, opts ...grpc.CallOption) (*Media, error)
+	ExportMedia(ctx context.Context, in *ExportMediaRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Media], error)
+}
+
+type mediaServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMediaServiceClient(cc grpc.ClientConnInterface) MediaServiceClient {
+	return &mediaServiceClient{cc}
+}
+
+func (c *mediaServiceClient) GetMediaByID(ctx context.Context, in *GetMediaByIDRequest, opts ...grpc.CallOption) (*Media, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Media)
+	err := c.cc.Invoke(ctx, MediaService_GetMediaByID_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mediaServiceClient) GetMediasByName(ctx context.Context, in *GetMediasByNameRequest, opts ...grpc.CallOption) (*MediaList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MediaList)
+	err := c.cc.Invoke(ctx, MediaService_GetMediasByName_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mediaServiceClient) SaveMedia(ctx context.Context, in *SaveMediaRequest, opts ...grpc.CallOption) (*Media, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Media)
+	err := c.cc.Invoke(ctx, MediaService_SaveMedia_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mediaServiceClient) UpdateMedia(ctx context.Context, in *SaveMediaRequest, opts ...grpc.CallOption) (*Media, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Media)
+	err := c.cc.Invoke(ctx, MediaService_UpdateMedia_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mediaServiceClient) SearchKinopoisk(ctx context.Context, in *SearchKinopoiskRequest, opts ...grpc.CallOption) (*MediaList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MediaList)
+	err := c.cc.Invoke(ctx, MediaService_SearchKinopoisk_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mediaServiceClient) DeleteMedia(ctx context.Context, in *DeleteMediaRequest, opts ...grpc.CallOption) (*DeleteMediaResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteMediaResponse)
+	err := c.cc.Invoke(ctx, MediaService_DeleteMedia_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mediaServiceClient) ListMedia(ctx context.Context, in *ListMediaRequest, opts ...grpc.CallOption) (*ListMediaResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListMediaResponse)
+	err := c.cc.Invoke(ctx, MediaService_ListMedia_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mediaServiceClient) RestoreMedia(ctx context.Context, in *RestoreMediaRequest, opts ...grpc.CallOption) (*Media, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Media)
+	err := c.cc.Invoke(ctx, MediaService_RestoreMedia_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mediaServiceClient) PurgeMedia(ctx context.Context, in *PurgeMediaRequest, opts ...grpc.CallOption) (*DeleteMediaResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteMediaResponse)
+	err := c.cc.Invoke(ctx, MediaService_PurgeMedia_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mediaServiceClient) GetMediaHistory(ctx context.Context, in *GetMediaHistoryRequest, opts ...grpc.CallOption) (*GetMediaHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetMediaHistoryResponse)
+	err := c.cc.Invoke(ctx, MediaService_GetMediaHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mediaServiceClient) WatchMediaChanges(ctx context.Context, in *WatchMediaChangesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[MediaHistoryEntry], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &MediaService_ServiceDesc.Streams[0], MediaService_WatchMediaChanges_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchMediaChangesRequest, MediaHistoryEntry]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MediaService_WatchMediaChangesClient = grpc.ServerStreamingClient[MediaHistoryEntry]
+
+func (c *mediaServiceClient) SearchMediaStream(ctx context.Context, in *GetMediasByNameRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SearchMediaStreamResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &MediaService_ServiceDesc.Streams[1], MediaService_SearchMediaStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[GetMediasByNameRequest, SearchMediaStreamResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MediaService_SearchMediaStreamClient = grpc.ServerStreamingClient[SearchMediaStreamResponse]
+
+func (c *mediaServiceClient) ImportMedia(ctx context.Context, in *ImportMediaRequest, opts ...grpc.CallOption) (*Media, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Media)
+	err := c.cc.Invoke(ctx, MediaService_ImportMedia_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mediaServiceClient) ExportMedia(ctx context.Context, in *ExportMediaRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Media], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &MediaService_ServiceDesc.Streams[2], MediaService_ExportMedia_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExportMediaRequest, Media]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MediaService_ExportMediaClient = grpc.ServerStreamingClient[Media]
+
+// MediaServiceServer is the server API for MediaService service.
+// All implementations must embed UnimplementedMediaServiceServer
+// for forward compatibility.
+type MediaServiceServer interface {
+	GetMediaByID(context.Context, *GetMediaByIDRequest) (*Media, error)
+	GetMediasByName(context.Context, *GetMediasByNameRequest) (*MediaList, error)
+	SaveMedia(context.Context, *SaveMediaRequest) (*Media, error)
+	UpdateMedia(context.Context, *SaveMediaRequest) (*Media, error)
+	SearchKinopoisk(context.Context, *SearchKinopoiskRequest) (*MediaList, error)
+	DeleteMedia(context.Context, *DeleteMediaRequest) (*DeleteMediaResponse, error)
+	ListMedia(context.Context, *ListMediaRequest) (*ListMediaResponse, error)
+	RestoreMedia(context.Context, *RestoreMediaRequest) (*Media, error)
+	PurgeMedia(context.Context, *PurgeMediaRequest) (*DeleteMediaResponse, error)
+	GetMediaHistory(context.Context, *GetMediaHistoryRequest) (*GetMediaHistoryResponse, error)
+	WatchMediaChanges(*WatchMediaChangesRequest, grpc.ServerStreamingServer[MediaHistoryEntry]) error
+	SearchMediaStream(*GetMediasByNameRequest, grpc.ServerStreamingServer[SearchMediaStreamResponse]) error
+	ImportMedia(context.Context, *ImportMediaRequest) (*Media, error)
+	ExportMedia(*ExportMediaRequest, grpc.ServerStreamingServer[Media]) error
+	mustEmbedUnimplementedMediaServiceServer()
+}
+
+// UnimplementedMediaServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedMediaServiceServer struct{}
+
+func (UnimplementedMediaServiceServer) GetMediaByID(context.Context, *GetMediaByIDRequest) (*Media, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMediaByID not implemented")
+}
+func (UnimplementedMediaServiceServer) GetMediasByName(context.Context, *GetMediasByNameRequest) (*MediaList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMediasByName not implemented")
+}
+func (UnimplementedMediaServiceServer) SaveMedia(context.Context, *SaveMediaRequest) (*Media, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SaveMedia not implemented")
+}
+func (UnimplementedMediaServiceServer) UpdateMedia(context.Context, *SaveMediaRequest) (*Media, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateMedia not implemented")
+}
+func (UnimplementedMediaServiceServer) SearchKinopoisk(context.Context, *SearchKinopoiskRequest) (*MediaList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchKinopoisk not implemented")
+}
+func (UnimplementedMediaServiceServer) DeleteMedia(context.Context, *DeleteMediaRequest) (*DeleteMediaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteMedia not implemented")
+}
+func (UnimplementedMediaServiceServer) ListMedia(context.Context, *ListMediaRequest) (*ListMediaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMedia not implemented")
+}
+func (UnimplementedMediaServiceServer) RestoreMedia(context.Context, *RestoreMediaRequest) (*Media, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreMedia not implemented")
+}
+func (UnimplementedMediaServiceServer) PurgeMedia(context.Context, *PurgeMediaRequest) (*DeleteMediaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PurgeMedia not implemented")
+}
+func (UnimplementedMediaServiceServer) GetMediaHistory(context.Context, *GetMediaHistoryRequest) (*GetMediaHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMediaHistory not implemented")
+}
+func (UnimplementedMediaServiceServer) WatchMediaChanges(*WatchMediaChangesRequest, grpc.ServerStreamingServer[MediaHistoryEntry]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchMediaChanges not implemented")
+}
+func (UnimplementedMediaServiceServer) SearchMediaStream(*GetMediasByNameRequest, grpc.ServerStreamingServer[SearchMediaStreamResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method SearchMediaStream not implemented")
+}
+func (UnimplementedMediaServiceServer) ImportMedia(context.Context, *ImportMediaRequest) (*Media, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportMedia not implemented")
+}
+func (UnimplementedMediaServiceServer) ExportMedia(*ExportMediaRequest, grpc.ServerStreamingServer[Media]) error {
+	return status.Errorf(codes.Unimplemented, "method ExportMedia not implemented")
+}
+func (UnimplementedMediaServiceServer) mustEmbedUnimplementedMediaServiceServer() {}
+func (UnimplementedMediaServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeMediaServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MediaServiceServer will
+// result in compilation errors.
+type UnsafeMediaServiceServer interface {
+	mustEmbedUnimplementedMediaServiceServer()
+}
+
+func RegisterMediaServiceServer(s grpc.ServiceRegistrar, srv MediaServiceServer) {
+	// If the following call pancis, it indicates UnimplementedMediaServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&MediaService_ServiceDesc, srv)
+}
+
+func _MediaService_GetMediaByID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMediaByIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaServiceServer).GetMediaByID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MediaService_GetMediaByID_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MediaServiceServer).GetMediaByID(ctx, req.(*GetMediaByIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MediaService_GetMediasByName_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMediasByNameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaServiceServer).GetMediasByName(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MediaService_GetMediasByName_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MediaServiceServer).GetMediasByName(ctx, req.(*GetMediasByNameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MediaService_SaveMedia_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SaveMediaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaServiceServer).SaveMedia(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MediaService_SaveMedia_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MediaServiceServer).SaveMedia(ctx, req.(*SaveMediaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MediaService_UpdateMedia_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SaveMediaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaServiceServer).UpdateMedia(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MediaService_UpdateMedia_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MediaServiceServer).UpdateMedia(ctx, req.(*SaveMediaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MediaService_SearchKinopoisk_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchKinopoiskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaServiceServer).SearchKinopoisk(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MediaService_SearchKinopoisk_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MediaServiceServer).SearchKinopoisk(ctx, req.(*SearchKinopoiskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MediaService_DeleteMedia_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteMediaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaServiceServer).DeleteMedia(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MediaService_DeleteMedia_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MediaServiceServer).DeleteMedia(ctx, req.(*DeleteMediaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MediaService_ListMedia_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMediaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaServiceServer).ListMedia(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MediaService_ListMedia_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MediaServiceServer).ListMedia(ctx, req.(*ListMediaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MediaService_RestoreMedia_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreMediaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaServiceServer).RestoreMedia(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MediaService_RestoreMedia_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MediaServiceServer).RestoreMedia(ctx, req.(*RestoreMediaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MediaService_PurgeMedia_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurgeMediaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaServiceServer).PurgeMedia(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MediaService_PurgeMedia_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MediaServiceServer).PurgeMedia(ctx, req.(*PurgeMediaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MediaService_GetMediaHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMediaHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaServiceServer).GetMediaHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MediaService_GetMediaHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MediaServiceServer).GetMediaHistory(ctx, req.(*GetMediaHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MediaService_WatchMediaChanges_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchMediaChangesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MediaServiceServer).WatchMediaChanges(m, &grpc.GenericServerStream[WatchMediaChangesRequest, MediaHistoryEntry]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MediaService_WatchMediaChangesServer = grpc.ServerStreamingServer[MediaHistoryEntry]
+
+func _MediaService_SearchMediaStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetMediasByNameRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MediaServiceServer).SearchMediaStream(m, &grpc.GenericServerStream[GetMediasByNameRequest, SearchMediaStreamResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MediaService_SearchMediaStreamServer = grpc.ServerStreamingServer[SearchMediaStreamResponse]
+
+func _MediaService_ImportMedia_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportMediaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaServiceServer).ImportMedia(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MediaService_ImportMedia_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MediaServiceServer).ImportMedia(ctx, req.(*ImportMediaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MediaService_ExportMedia_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportMediaRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MediaServiceServer).ExportMedia(m, &grpc.GenericServerStream[ExportMediaRequest, Media]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MediaService_ExportMediaServer = grpc.ServerStreamingServer[Media]
+
+// MediaService_ServiceDesc is the grpc.ServiceDesc for MediaService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MediaService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "media.MediaService",
+	HandlerType: (*MediaServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetMediaByID",
+			Handler:    _MediaService_GetMediaByID_Handler,
+		},
+		{
+			MethodName: "GetMediasByName",
+			Handler:    _MediaService_GetMediasByName_Handler,
+		},
+		{
+			MethodName: "SaveMedia",
+			Handler:    _MediaService_SaveMedia_Handler,
+		},
+		{
+			MethodName: "UpdateMedia",
+			Handler:    _MediaService_UpdateMedia_Handler,
+		},
+		{
+			MethodName: "SearchKinopoisk",
+			Handler:    _MediaService_SearchKinopoisk_Handler,
+		},
+		{
+			MethodName: "DeleteMedia",
+			Handler:    _MediaService_DeleteMedia_Handler,
+		},
+		{
+			MethodName: "ListMedia",
+			Handler:    _MediaService_ListMedia_Handler,
+		},
+		{
+			MethodName: "RestoreMedia",
+			Handler:    _MediaService_RestoreMedia_Handler,
+		},
+		{
+			MethodName: "PurgeMedia",
+			Handler:    _MediaService_PurgeMedia_Handler,
+		},
+		{
+			MethodName: "GetMediaHistory",
+			Handler:    _MediaService_GetMediaHistory_Handler,
+		},
+		{
+			MethodName: "ImportMedia",
+			Handler:    _MediaService_ImportMedia_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchMediaChanges",
+			Handler:       _MediaService_WatchMediaChanges_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SearchMediaStream",
+			Handler:       _MediaService_SearchMediaStream_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ExportMedia",
+			Handler:       _MediaService_ExportMedia_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "media.proto",
+}